@@ -5,7 +5,6 @@ import (
 	"crypto/sha1"
 	"embed"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,9 +12,12 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	containerutils "github.com/syntasso/kratix-cli/cmd/container_utils"
 	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix-cli/internal"
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/yaml"
 )
@@ -23,22 +25,52 @@ import (
 //go:embed templates/workflows/*
 var workflowTemplates embed.FS
 
-var supportedLanguages = []string{"go", "bash", "python"}
+var supportedLanguages = []string{"go", "bash", "python", "typescript", "java", "rust", "kustomize", "helm"}
+
+// languageTemplate describes the files generated for a container language.
+// Every file is rendered from templates/workflows/<language>/<file>.tpl.
+type languageTemplate struct {
+	fileExtension string
+	// dependencyFiles are written next to the pipeline script in scripts/
+	dependencyFiles []string
+	// resourceFiles are written to the container's resources/ directory
+	resourceFiles       []string
+	confirmationMessage string
+}
 
-var languageMatrix = map[string]interface{}{
-	"go": map[string]string{
-		"fileExtension":       "go",
-		"templateDir":         "go",
-		"confirmationMessage": "run 'go mod init' and 'go mod tidy' to manage your script's dependencies",
+var languageMatrix = map[string]languageTemplate{
+	"go": {
+		fileExtension:   "go",
+		dependencyFiles: []string{"go.mod"},
+	},
+	"bash": {
+		fileExtension: "sh",
+	},
+	"python": {
+		fileExtension:   "py",
+		dependencyFiles: []string{"requirements.txt"},
+	},
+	"typescript": {
+		fileExtension:   "ts",
+		dependencyFiles: []string{"package.json", "tsconfig.json"},
+	},
+	"java": {
+		fileExtension:   "java",
+		dependencyFiles: []string{"pom.xml"},
 	},
-	"bash": map[string]string{
-		"fileExtension": "sh",
-		"templateDir":   ".",
+	"rust": {
+		fileExtension:   "rs",
+		dependencyFiles: []string{"Cargo.toml"},
 	},
-	"python": map[string]string{
-		"fileExtension":       "py",
-		"templateDir":         "python",
-		"confirmationMessage": "",
+	"kustomize": {
+		fileExtension:       "sh",
+		resourceFiles:       []string{"kustomization.yaml"},
+		confirmationMessage: "add your manifests to the resources directory and list them in resources/kustomization.yaml",
+	},
+	"helm": {
+		fileExtension:       "sh",
+		resourceFiles:       []string{"values.yaml"},
+		confirmationMessage: "copy your chart into resources/chart or set the CHART environment variable on the container",
 	},
 }
 
@@ -52,19 +84,51 @@ var addContainerCmd = &cobra.Command{
   kratix add container resource/configure/instance --image syntasso/postgres-resource:v1.0.0
 
   # add a new promise configure container to pipeline 'pipeline0', with the container name 'deploy-deps'
-  kratix add container promise/configure/pipeline0 --image syntasso/postgres-resource:v1.0.0 --name deploy-deps`,
+  kratix add container promise/configure/pipeline0 --image syntasso/postgres-resource:v1.0.0 --name deploy-deps
+
+  # add a new resource configure container written in TypeScript
+  kratix add container resource/configure/instance --image syntasso/postgres-resource:v1.0.0 --language typescript
+
+  # add a container that renders the kustomization in its resources directory
+  kratix add container resource/configure/instance --image syntasso/postgres-resource:v1.0.0 --language kustomize
+
+  # add a container that reads a password from a Secret and runs with extra permissions
+  kratix add container resource/configure/instance --image syntasso/postgres-resource:v1.0.0 --env-from-secret db-creds:password=DB_PASSWORD --rbac "apiGroups=apps,resources=deployments,verbs=get;list"
+
+  # add a container from your organisation's template, stored in a git repository
+  kratix add container resource/configure/instance --image myorg/instance:v1.0.0 --template "git::https://github.com/myorg/kratix-templates.git//go?ref=v1.2.0"`,
 	RunE: AddContainer,
 	Args: cobra.ExactArgs(1),
 }
 
-var image, containerName, language string
+const containerTemplateEnvVar = "KRATIX_CONTAINER_TEMPLATE"
+
+var image, containerName, language, containerTemplate string
+
+// containerTemplateDir is the local directory --template resolved to, if any
+var containerTemplateDir string
+
+// containerTemplateValues are the values available to container templates
+type containerTemplateValues struct {
+	PromiseName   string
+	Lifecycle     string
+	Action        string
+	Pipeline      string
+	ContainerName string
+	Image         string
+	Language      string
+}
 
 func init() {
 	addCmd.AddCommand(addContainerCmd)
 	addContainerCmd.Flags().StringVarP(&image, "image", "i", "", "The image used by this container.")
 	addContainerCmd.Flags().StringVarP(&containerName, "name", "n", "", "The container name used for this container.")
 	addContainerCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
-	addContainerCmd.Flags().StringVarP(&language, "language", "l", "bash", "Language to use for the pipeline script. Currently supports Bash, Go and Python.")
+	addContainerCmd.Flags().StringVarP(&language, "language", "l", "bash", "Language to use for the pipeline script. One of: "+strings.Join(supportedLanguages, ", ")+".")
+	addContainerCmd.Flags().StringVarP(&containerTemplate, "template", "t", "", "Directory or git URL of a custom container template, laid out like the built-in language templates. "+
+		"Defaults to the value of the "+containerTemplateEnvVar+" environment variable, unless --language is set.")
+	addContainerCmd.MarkFlagsMutuallyExclusive("language", "template")
+	addContainerOptionFlags(addContainerCmd)
 	addContainerCmd.MarkFlagRequired("image")
 }
 
@@ -73,7 +137,22 @@ func AddContainer(cmd *cobra.Command, args []string) error {
 		containerName = generateContainerName(image)
 	}
 
-	if !slices.Contains(supportedLanguages, language) {
+	// an explicit --language picks a built-in template over the default one
+	if containerTemplate == "" && !cmd.Flags().Changed("language") {
+		containerTemplate = os.Getenv(containerTemplateEnvVar)
+	}
+
+	if containerTemplate != "" {
+		templateDir, cleanup, err := internal.FetchTemplateSource(containerTemplate)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		containerTemplateDir = templateDir
+		if language, err = customTemplateLanguage(templateDir); err != nil {
+			return err
+		}
+	} else if !slices.Contains(supportedLanguages, language) {
 		return fmt.Errorf("invalid language: %s is not supported by the kratix cli", language)
 	}
 
@@ -88,6 +167,9 @@ func AddContainer(cmd *cobra.Command, args []string) error {
 	}
 
 	pipelineScriptFilename := pipelineScriptFilename(language)
+	if containerTemplateDir != "" {
+		pipelineScriptFilename = customTemplateScriptFilename(containerTemplateDir)
+	}
 	scriptsPath := filepath.Join("workflows", containerArgs.Lifecycle, containerArgs.Action, containerArgs.Pipeline, containerName, "scripts", pipelineScriptFilename)
 	logConfirmationMessages(scriptsPath, language)
 	return nil
@@ -98,22 +180,17 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 		promiseDir = dir
 	}
 
-	if c.Lifecycle != "promise" && c.Lifecycle != "resource" {
-		return fmt.Errorf("invalid lifecycle: %s, expected one of: promise, resource", c.Lifecycle)
-	}
-
-	if c.Action != "configure" && c.Action != "delete" {
-		return fmt.Errorf("invalid action: %s, expected one of: configure, delete", c.Action)
-	}
-
-	if c.Pipeline == "" {
-		return fmt.Errorf("pipeline name cannot be empty")
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
 	}
 
 	container := v1alpha1.Container{
 		Name:  containerName,
 		Image: image,
 	}
+	if err := containerOpts.apply(&container); err != nil {
+		return err
+	}
 
 	workflowPath := filepath.Join("workflows", c.Lifecycle, c.Action)
 	var promise v1alpha1.Promise
@@ -132,7 +209,7 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 	var fileBytes []byte
 	var err error
 	if splitFiles && workflowFileFound(filePath) {
-		fileBytes, err = os.ReadFile(filePath)
+		fileBytes, err = promiseFiles.readFile(filePath)
 		if err != nil {
 			return err
 		}
@@ -145,7 +222,7 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 	}
 
 	if !splitFiles {
-		fileBytes, err := os.ReadFile(filePath)
+		fileBytes, err := promiseFiles.readFile(filePath)
 		if err != nil {
 			return err
 		}
@@ -177,12 +254,34 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 			}
 			pipelines[pipelineIdx].Spec.Containers[containerIdx] = container
 		}
+		if err := pipelineOpts.apply(&pipelines[pipelineIdx]); err != nil {
+			return err
+		}
 
 		pipelinesUnstructured, err = pipelineutils.PipelinesToUnstructured(pipelines)
 		if err != nil {
 			return err
 		}
 	} else {
+		spec := map[string]interface{}{
+			"containers": []interface{}{container},
+		}
+		if pipelineOpts.isSet() {
+			newPipeline := v1alpha1.Pipeline{}
+			if err := pipelineOpts.apply(&newPipeline); err != nil {
+				return err
+			}
+			newSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&newPipeline.Spec)
+			if err != nil {
+				return err
+			}
+			if len(newPipeline.Spec.Volumes) > 0 {
+				spec["volumes"] = newSpec["volumes"]
+			}
+			if newPipeline.Spec.RBAC.ServiceAccount != "" || len(newPipeline.Spec.RBAC.Permissions) > 0 {
+				spec["rbac"] = newSpec["rbac"]
+			}
+		}
 		pipeline := unstructured.Unstructured{
 			Object: map[string]interface{}{
 				"apiVersion": "platform.kratix.io/v1alpha1",
@@ -190,9 +289,7 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 				"metadata": map[string]interface{}{
 					"name": c.Pipeline,
 				},
-				"spec": map[string]interface{}{
-					"containers": []interface{}{container},
-				},
+				"spec": spec,
 			},
 		}
 		pipelinesUnstructured, err = pipelineutils.PipelinesToUnstructured(pipelines)
@@ -216,11 +313,28 @@ func generateWorkflow(c *pipelineutils.PipelineCmdArgs, containerName, image, pr
 			return err
 		}
 	}
-	if err := generatePipelineDirFiles(promiseDir, workflowPath, c.Pipeline, containerName, language); err != nil {
+	promiseName := promise.GetName()
+	if splitFiles {
+		name, err := splitPromiseName(promiseDir)
+		if err != nil && containerTemplateDir != "" {
+			return err
+		}
+		promiseName = name
+	}
+	templateValues := containerTemplateValues{
+		PromiseName:   promiseName,
+		Lifecycle:     c.Lifecycle,
+		Action:        c.Action,
+		Pipeline:      c.Pipeline,
+		ContainerName: containerName,
+		Image:         image,
+		Language:      language,
+	}
+	if err := generatePipelineDirFiles(promiseDir, workflowPath, templateValues); err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(filePath, fileBytes, filePerm); err != nil {
+	if err := promiseFiles.writeFile(filePath, fileBytes); err != nil {
 		return err
 	}
 	fmt.Printf("generated the %s/%s/%s/%s in %s \n", c.Lifecycle, c.Action, c.Pipeline, containerName, filePath)
@@ -289,44 +403,109 @@ func updatePipeline(lifecycle, action string, pipelines []unstructured.Unstructu
 	}
 }
 
-func generatePipelineDirFiles(promiseDir, workflowDirectory, pipelineName, containerName, language string) error {
-	containerFileDirectory := filepath.Join(workflowDirectory, pipelineName, containerName)
+func generatePipelineDirFiles(promiseDir, workflowDirectory string, values containerTemplateValues) error {
+	containerFileDirectory := filepath.Join(workflowDirectory, values.Pipeline, values.ContainerName)
 	containerScriptsDirectory := filepath.Join(containerFileDirectory, "scripts")
 	resourcesDir := filepath.Join(promiseDir, containerFileDirectory, "resources")
 
-	templates := getTemplates(containerFileDirectory, containerScriptsDirectory, language)
+	if containerTemplateDir != "" {
+		if err := generateFromCustomTemplate(containerTemplateDir, promiseDir, containerFileDirectory, values); err != nil {
+			return err
+		}
+	} else {
+		templates := getTemplates(containerFileDirectory, containerScriptsDirectory, values.Language, values.Action)
+		for _, resourceFile := range languageMatrix[values.Language].resourceFiles {
+			templates[filepath.Join(containerFileDirectory, "resources", resourceFile)] = fmt.Sprintf("templates/workflows/%s/%s.tpl", values.Language, resourceFile)
+		}
 
-	if err := templateFiles(workflowTemplates, promiseDir, templates, nil); err != nil {
-		return err
+		if err := templateFiles(workflowTemplates, promiseDir, templates, values); err != nil {
+			return err
+		}
 	}
-	if _, err := os.Stat(resourcesDir); errors.Is(err, os.ErrNotExist) {
-		return os.Mkdir(resourcesDir, os.ModePerm)
+	return promiseFiles.mkdirAll(resourcesDir)
+}
+
+// generateFromCustomTemplate renders a user-provided container template.
+// Files ending in .tpl are rendered and have the suffix removed, other files
+// are copied as-is. Dockerfile, build.yaml and anything under resources/ or
+// scripts/ keep their place in the container directory; all other files are
+// written to scripts/, matching the layout of the built-in templates.
+func generateFromCustomTemplate(templateDir, promiseDir, containerFileDirectory string, values containerTemplateValues) error {
+	return templateDirectory(templateDir, promiseDir, func(relPath string) string {
+		return filepath.Join(containerFileDirectory, customTemplateOutputPath(relPath))
+	}, values)
+}
+
+func customTemplateOutputPath(relPath string) string {
+	switch strings.SplitN(filepath.ToSlash(relPath), "/", 2)[0] {
+	case "Dockerfile", containerutils.BuildConfigFileName, "resources", "scripts":
+		return relPath
 	}
-	return nil
+	return filepath.Join("scripts", relPath)
 }
 
-func pipelineScriptFilename(language string) string {
-	extension := languageMatrix[language].(map[string]string)["fileExtension"]
-	return fmt.Sprintf("pipeline.%s", extension)
+// customTemplateScriptFilename returns the name of the pipeline script
+// provided by a custom template, if it follows the pipeline.<ext> convention.
+func customTemplateScriptFilename(templateDir string) string {
+	for _, dir := range []string{templateDir, filepath.Join(templateDir, "scripts")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasPrefix(entry.Name(), "pipeline.") {
+				return strings.TrimSuffix(entry.Name(), ".tpl")
+			}
+		}
+	}
+	return ""
 }
 
-func filesGeneratedWithSplit(dir string) bool {
-	if _, err := os.Stat(dir + "/api.yaml"); errors.Is(err, os.ErrNotExist) {
-		return false
+// splitPromiseName returns the name of a Promise generated with --split,
+// which is only recorded in the generator config written by `kratix init`.
+func splitPromiseName(promiseDir string) (string, error) {
+	configPath := filepath.Join(promiseDir, generatorDirName, generatorFileName)
+	if !promiseFiles.exists(configPath) {
+		return "", fmt.Errorf("cannot find the Promise name for a Promise generated with --split: %s not found", configPath)
+	}
+	config, err := loadGeneratorConfig(promiseDir)
+	if err != nil {
+		return "", err
+	}
+	if len(config.Args) == 0 {
+		return "", fmt.Errorf("cannot find the Promise name for a Promise generated with --split: %s has no arguments", configPath)
 	}
+	return config.Args[0], nil
+}
 
-	if _, err := os.Stat(dir + "/dependencies.yaml"); errors.Is(err, os.ErrNotExist) {
-		return false
+// customTemplateLanguage returns the language of a custom template's
+// pipeline script, going by its extension. Scripts in languages without a
+// built-in template, such as pipeline.rb, use their extension as language.
+func customTemplateLanguage(templateDir string) (string, error) {
+	script := customTemplateScriptFilename(templateDir)
+	if script == "" {
+		return "", fmt.Errorf("template %s has no pipeline script; add a pipeline.<extension> file to it or its scripts directory", templateDir)
+	}
+	extension := strings.TrimPrefix(filepath.Ext(script), ".")
+	for _, language := range supportedLanguages {
+		if languageMatrix[language].fileExtension == extension {
+			return language, nil
+		}
 	}
+	return extension, nil
+}
 
-	return true
+func pipelineScriptFilename(language string) string {
+	extension := languageMatrix[language].fileExtension
+	return fmt.Sprintf("pipeline.%s", extension)
+}
+
+func filesGeneratedWithSplit(dir string) bool {
+	return promiseFiles.exists(filepath.Join(dir, apiFileName)) && promiseFiles.exists(filepath.Join(dir, dependenciesFileName))
 }
 
 func workflowFileFound(workflowFilePath string) bool {
-	if _, err := os.Stat(workflowFilePath); errors.Is(err, os.ErrNotExist) {
-		return false
-	}
-	return true
+	return promiseFiles.exists(workflowFilePath)
 }
 
 func getPipelineIdx(pipelines []v1alpha1.Pipeline, pipelineName string) (int, error) {
@@ -348,22 +527,31 @@ func getContainerIdx(pipeline v1alpha1.Pipeline, containerName string) int {
 	return -1
 }
 
-func getTemplates(containerFileDirectory, containerScriptsDirectory, language string) map[string]string {
+func getTemplates(containerFileDirectory, containerScriptsDirectory, language, action string) map[string]string {
 	pipelineScriptFilename := pipelineScriptFilename(language)
 	pipelineScriptTemplateFilepath := fmt.Sprintf("templates/workflows/%s/%s.tpl", language, pipelineScriptFilename)
+	switch action {
+	case "delete", healthCheckAction:
+		// delete and health check pipelines get their own script, still named
+		// pipeline.<ext> so that the Dockerfile is the same for every action
+		pipelineScriptTemplateFilepath = fmt.Sprintf("templates/workflows/%s/%s.%s.tpl", language, action, languageMatrix[language].fileExtension)
+	}
 	dockerfileTemplateFilepath := fmt.Sprintf("templates/workflows/%s/Dockerfile.tpl", language)
 
 	templates := map[string]string{
 		filepath.Join(containerScriptsDirectory, pipelineScriptFilename): pipelineScriptTemplateFilepath,
 		filepath.Join(containerFileDirectory, "Dockerfile"):              dockerfileTemplateFilepath,
 	}
+	for _, dependencyFile := range languageMatrix[language].dependencyFiles {
+		templates[filepath.Join(containerScriptsDirectory, dependencyFile)] = fmt.Sprintf("templates/workflows/%s/%s.tpl", language, dependencyFile)
+	}
 	return templates
 }
 
 func logConfirmationMessages(scriptsPath, language string) {
 	fmt.Printf("Customise your container by editing %s \n", scriptsPath)
-	if language == "go" {
-		fmt.Printf("For %s containers, %s\n", language, languageMatrix[language].(map[string]string)["confirmationMessage"])
+	if message := languageMatrix[language].confirmationMessage; message != "" {
+		fmt.Printf("For %s containers, %s\n", language, message)
 	}
 	fmt.Println("Don't forget to build and push your image!")
 }
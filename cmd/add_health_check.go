@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	healthCheckAction   = "healthcheck"
+	healthCheckPipeline = "instance"
+
+	// healthDefinitionContainer writes the HealthDefinition of each resource
+	// from the resource configure workflow
+	healthDefinitionContainer = "health-definition"
+	healthDefinitionImage     = "my-registry.io/my-org/kratix/health-definition:v0.0.1"
+	healthDefinitionFileName  = "health-definition.yaml"
+	// healthDefinitionOutputDir is the directory of /kratix/output the
+	// HealthDefinition is written to, so that it can be scheduled on its own
+	healthDefinitionOutputDir = "health"
+)
+
+var healthCheckLanguages = []string{"bash", "go", "python"}
+
+var addHealthCheckCmd = &cobra.Command{
+	Use:   "health-check --schedule CRON-SCHEDULE --image CONTAINER-IMAGE",
+	Short: "Adds a health check for the Promise's resources",
+	Long: `Adds a health check for the Promise's resources.
+
+A health-definition container is added to the resource configure workflow. For
+each resource, it writes a HealthDefinition to /kratix/output/health, from the
+template in its resources/health-definition.yaml. The health check container
+files are written to workflows/resource/healthcheck/instance/CONTAINER-NAME.
+
+The health check pipeline runs on the given schedule and writes the health of
+the resource to /kratix/output/healthrecord.yaml. Run it locally with:
+
+  kratix test pipeline resource/healthcheck/instance`,
+	Example: `  # check the health of every resource every five minutes
+  kratix add health-check --schedule "*/5 * * * *" --image myorg/db-health:v1.0.0
+
+  # run the health check on Destinations labelled env=prod, with a Python script
+  kratix add health-check --schedule "@hourly" --image myorg/db-health:v1.0.0 --destination-selector env=prod --language python`,
+	RunE: AddHealthCheck,
+	Args: cobra.NoArgs,
+}
+
+var healthCheckSchedule, healthCheckImage, healthCheckContainerName, healthCheckLanguage, healthCheckPromiseName string
+var healthCheckSelectors []string
+
+// healthDefinition describes how and when the health of a resource is
+// checked. The health-definition container sets the metadata, resourceRef and
+// input of each resource's HealthDefinition.
+type healthDefinition struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Metadata   healthMetadata       `json:"metadata,omitempty"`
+	Spec       healthDefinitionSpec `json:"spec"`
+}
+
+type healthMetadata struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type healthDefinitionSpec struct {
+	PromiseRef  v1alpha1.PromiseRef   `json:"promiseRef"`
+	ResourceRef *v1alpha1.ResourceRef `json:"resourceRef,omitempty"`
+	Input       string                `json:"input,omitempty"`
+	Schedule    string                `json:"schedule"`
+	Workflow    v1alpha1.Pipeline     `json:"workflow"`
+}
+
+// healthCheck is the health check of a Promise: the HealthDefinition template
+// and the resource configure pipeline writing it.
+type healthCheck struct {
+	definition *healthDefinition
+	// pipeline is the resource configure pipeline of the health-definition
+	// container
+	pipeline string
+}
+
+func (h *healthCheck) resourcesDir(promiseDir string) string {
+	return filepath.Join(promiseDir, "workflows", "resource", "configure", h.pipeline, healthDefinitionContainer, "resources")
+}
+
+func init() {
+	addCmd.AddCommand(addHealthCheckCmd)
+	addHealthCheckCmd.Flags().StringVar(&healthCheckSchedule, "schedule", "", "Cron schedule the health check runs on, e.g. \"*/5 * * * *\". Required when the Promise has no health check yet.")
+	addHealthCheckCmd.Flags().StringVarP(&healthCheckImage, "image", "i", "", "The image used by the health check container.")
+	addHealthCheckCmd.Flags().StringVarP(&healthCheckContainerName, "name", "n", "", "The container name used for this container.")
+	addHealthCheckCmd.Flags().StringArrayVar(&healthCheckSelectors, "destination-selector", nil, "Label the Destinations running the health check must have, as KEY=VALUE. Can be repeated.")
+	addHealthCheckCmd.Flags().StringVarP(&healthCheckLanguage, "language", "l", "bash", "Language to use for the health check script. One of: "+strings.Join(healthCheckLanguages, ", ")+".")
+	addHealthCheckCmd.Flags().StringVar(&healthCheckPromiseName, "promise-name", "", "Name of the Promise. Defaults to the name in promise.yaml; required for Promises generated with --split.")
+	addHealthCheckCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
+	addHealthCheckCmd.MarkFlagRequired("image")
+}
+
+func AddHealthCheck(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(healthCheckLanguages, healthCheckLanguage) {
+		return fmt.Errorf("invalid language: %s, health checks support: %s", healthCheckLanguage, strings.Join(healthCheckLanguages, ", "))
+	}
+	if healthCheckSchedule != "" {
+		if err := validateCronSchedule(healthCheckSchedule); err != nil {
+			return err
+		}
+	}
+	if healthCheckContainerName == "" {
+		healthCheckContainerName = generateContainerName(healthCheckImage)
+	}
+
+	check, err := loadHealthCheck(dir)
+	if err != nil {
+		return err
+	}
+	if check.definition == nil {
+		if healthCheckSchedule == "" {
+			return fmt.Errorf("--schedule is required when adding the first health check")
+		}
+		promiseName, err := resolvePromiseName(dir, healthCheckPromiseName)
+		if err != nil {
+			return err
+		}
+		if err := addHealthDefinitionContainer(dir, check.pipeline); err != nil {
+			return err
+		}
+		check.definition = newHealthDefinition(promiseName)
+	}
+	definition := check.definition
+
+	if healthCheckSchedule != "" {
+		definition.Spec.Schedule = healthCheckSchedule
+	}
+	if len(healthCheckSelectors) > 0 {
+		matchLabels := map[string]string{}
+		for _, selector := range healthCheckSelectors {
+			key, value, ok := strings.Cut(selector, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid --destination-selector value %q, expected KEY=VALUE", selector)
+			}
+			matchLabels[key] = value
+		}
+		selectorBytes, err := yaml.Marshal([]v1alpha1.WorkflowDestinationSelectors{{Directory: healthDefinitionOutputDir, MatchLabels: matchLabels}})
+		if err != nil {
+			return err
+		}
+		if err := promiseFiles.writeFile(filepath.Join(check.resourcesDir(dir), "destination-selectors.yaml"), selectorBytes); err != nil {
+			return err
+		}
+	}
+
+	workflow := &definition.Spec.Workflow
+	if getContainerIdx(*workflow, healthCheckContainerName) != -1 {
+		return fmt.Errorf("image '%s' already exists in the health check", healthCheckContainerName)
+	}
+	workflow.Spec.Containers = append(workflow.Spec.Containers, v1alpha1.Container{
+		Name:  healthCheckContainerName,
+		Image: healthCheckImage,
+	})
+
+	values := containerTemplateValues{
+		PromiseName:   definition.Spec.PromiseRef.Name,
+		Lifecycle:     "resource",
+		Action:        healthCheckAction,
+		Pipeline:      healthCheckPipeline,
+		ContainerName: healthCheckContainerName,
+		Image:         healthCheckImage,
+		Language:      healthCheckLanguage,
+	}
+	if err := generatePipelineDirFiles(dir, filepath.Join("workflows", "resource", healthCheckAction), values); err != nil {
+		return err
+	}
+
+	definitionPath := filepath.Join(check.resourcesDir(dir), healthDefinitionFileName)
+	if err := writeHealthDefinition(definitionPath, definition); err != nil {
+		return err
+	}
+
+	fmt.Printf("generated the health check container %s in %s\n", healthCheckContainerName, definitionPath)
+	scriptsPath := filepath.Join("workflows", "resource", healthCheckAction, healthCheckPipeline, healthCheckContainerName, "scripts", pipelineScriptFilename(healthCheckLanguage))
+	fmt.Printf("Customise your health check by editing %s \n", scriptsPath)
+	fmt.Println("Test it locally with: kratix test pipeline resource/healthcheck/instance")
+	return nil
+}
+
+func newHealthDefinition(promiseName string) *healthDefinition {
+	definition := &healthDefinition{
+		APIVersion: "platform.kratix.io/v1alpha1",
+		Kind:       "HealthDefinition",
+		Spec: healthDefinitionSpec{
+			PromiseRef: v1alpha1.PromiseRef{Name: promiseName},
+		},
+	}
+	definition.Spec.Workflow.APIVersion = "platform.kratix.io/v1alpha1"
+	definition.Spec.Workflow.Kind = "Pipeline"
+	definition.Spec.Workflow.SetName(healthCheckPipeline)
+	return definition
+}
+
+// loadHealthCheck returns the Promise's health check. Its definition is nil
+// if the Promise has none, and its pipeline is then the resource configure
+// pipeline to add the health-definition container to.
+func loadHealthCheck(promiseDir string) (*healthCheck, error) {
+	w, err := loadWorkflowFile(promiseDir, &pipelineutils.PipelineCmdArgs{Lifecycle: "resource", Action: "configure"})
+	if err != nil {
+		return nil, err
+	}
+
+	check := &healthCheck{pipeline: healthCheckPipeline}
+	for _, pipeline := range w.pipelines {
+		if getContainerIdx(pipeline, healthDefinitionContainer) != -1 {
+			check.pipeline = pipeline.GetName()
+			definitionPath := filepath.Join(check.resourcesDir(promiseDir), healthDefinitionFileName)
+			fileBytes, err := promiseFiles.readFile(definitionPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the health definition: %w", err)
+			}
+			check.definition = &healthDefinition{}
+			if err := yaml.Unmarshal(fileBytes, check.definition); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", definitionPath, err)
+			}
+			return check, nil
+		}
+	}
+	if len(w.pipelines) > 0 {
+		check.pipeline = w.pipelines[len(w.pipelines)-1].GetName()
+	}
+	return check, nil
+}
+
+// addHealthDefinitionContainer adds the container writing the HealthDefinition
+// of each resource to the resource configure pipeline.
+func addHealthDefinitionContainer(promiseDir, pipeline string) error {
+	c := &pipelineutils.PipelineCmdArgs{Lifecycle: "resource", Action: "configure", Pipeline: pipeline}
+	if err := generateWorkflow(c, healthDefinitionContainer, healthDefinitionImage, promiseDir, false); err != nil {
+		return fmt.Errorf("error generating workflows for %s/%s/%s: %s", c.Lifecycle, c.Action, c.Pipeline, err)
+	}
+
+	scriptPath := filepath.Join("workflows", c.Lifecycle, c.Action, c.Pipeline, healthDefinitionContainer, "scripts", "pipeline.sh")
+	if err := templateFiles(workflowTemplates, promiseDir, map[string]string{
+		scriptPath: "templates/workflows/bash/health-definition.sh.tpl",
+	}, map[string]string{"OutputDir": healthDefinitionOutputDir}); err != nil {
+		return err
+	}
+	fmt.Printf("Build the %s image with: kratix build container %s/%s/%s --name %s\n", healthDefinitionContainer, c.Lifecycle, c.Action, c.Pipeline, healthDefinitionContainer)
+	return nil
+}
+
+func writeHealthDefinition(path string, definition *healthDefinition) error {
+	fileBytes, err := yaml.Marshal(definition)
+	if err != nil {
+		return err
+	}
+	return promiseFiles.writeFile(path, fileBytes)
+}
+
+// resolvePromiseName returns name if set, or the name of the Promise in
+// promise.yaml.
+func resolvePromiseName(promiseDir, name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if filesGeneratedWithSplit(promiseDir) {
+		return "", fmt.Errorf("cannot find the Promise name for a Promise generated with --split; set it with --promise-name")
+	}
+	promise, err := getPromise(filepath.Join(promiseDir, promiseFileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to find promise.yaml in directory: %v", err)
+	}
+	return promise.GetName(), nil
+}
+
+// validateCronSchedule checks that schedule is a five field cron expression
+// or one of the predefined @ schedules.
+func validateCronSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "@") {
+		if slices.Contains([]string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}, schedule) {
+			return nil
+		}
+		return fmt.Errorf("invalid schedule %q: unknown predefined schedule", schedule)
+	}
+	if len(strings.Fields(schedule)) != 5 {
+		return fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day-of-month month day-of-week)", schedule)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	containerutils "github.com/syntasso/kratix-cli/cmd/container_utils"
 	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
 	promiseutils "github.com/syntasso/kratix-cli/cmd/promise_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
 )
 
 // containerCmd represents the container command
@@ -33,6 +34,10 @@ var buildContainerCmd = &cobra.Command{
 
   # Build with podman
   kratix build container resource/configure/mypipeline --engine podman
+
+  # Per-container settings (platforms, buildArgs, target, secrets and context)
+  # are read from an optional build.yaml in the container directory, e.g.
+  # workflows/resource/configure/mypipeline/mycontainer/build.yaml
   `,
 	RunE: BuildContainer,
 }
@@ -73,7 +78,7 @@ func BuildContainer(cmd *cobra.Command, args []string) error {
 	var containersToBuild []string
 	if buildContainerOpts.BuildAllContainers {
 		for _, workflowType := range []string{"promise", "resource"} {
-			for _, action := range []string{"configure", "delete"} {
+			for _, action := range []string{"configure", "delete", healthCheckAction} {
 				workflowDir := filepath.Join(buildContainerOpts.Dir, "workflows", workflowType, action)
 				if _, err := os.Stat(workflowDir); os.IsNotExist(err) {
 					continue
@@ -99,9 +104,18 @@ func BuildContainer(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("error ParsePipelineCmdArgs: %s", err)
 		}
 
-		pipeline, err := pipelineutils.RetrievePipeline(promise, containerArgs)
-		if err != nil {
-			return fmt.Errorf("error RetrievePipeline: %s", err)
+		var pipeline *v1alpha1.Pipeline
+		if containerArgs.Lifecycle == "resource" && containerArgs.Action == healthCheckAction {
+			healthCheck, _, err := loadPipelineToRun(buildContainerOpts.Dir, containerArgs)
+			if err != nil {
+				return err
+			}
+			pipeline = &healthCheck
+		} else {
+			pipeline, err = pipelineutils.RetrievePipeline(promise, containerArgs)
+			if err != nil {
+				return fmt.Errorf("error RetrievePipeline: %s", err)
+			}
 		}
 
 		pipelineDir := filepath.Join(buildContainerOpts.Dir, "workflows", containerArgs.Lifecycle, containerArgs.Action, containerArgs.Pipeline)
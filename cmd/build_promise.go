@@ -88,7 +88,7 @@ func BuildPromise(cmd *cobra.Command, args []string) error {
 	}
 
 	if outputPath != "" {
-		return os.WriteFile(outputPath, promiseBytes, filePerm)
+		return promiseFiles.writeFile(outputPath, promiseBytes)
 	}
 
 	fmt.Println(string(promiseBytes))
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	containerutils "github.com/syntasso/kratix-cli/cmd/container_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// containerOptions are the container settings that can be set with flags on
+// add container and update container. Values ending in "-" remove the named
+// entry, matching update destination-selector.
+type containerOptions struct {
+	env             []string
+	envFromSecret   []string
+	command         string
+	args            string
+	imagePullPolicy string
+	volumeMounts    []string
+}
+
+// pipelineOptions are the settings of the pipeline a container belongs to.
+type pipelineOptions struct {
+	serviceAccount string
+	volumes        []string
+	permissions    []string
+}
+
+var (
+	containerOpts containerOptions
+	pipelineOpts  pipelineOptions
+)
+
+func addContainerOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&containerOpts.env, "env", nil, "Environment variable for the container, as KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringArrayVar(&containerOpts.envFromSecret, "env-from-secret", nil, "Environment variable read from a Secret, as SECRET-NAME:SECRET-KEY=ENV-NAME. Can be repeated.")
+	cmd.Flags().StringVar(&containerOpts.command, "command", "", "Command to run in the container, overriding the image entrypoint.")
+	cmd.Flags().StringVar(&containerOpts.args, "args", "", "Arguments to pass to the container command.")
+	cmd.Flags().StringVar(&containerOpts.imagePullPolicy, "image-pull-policy", "", "Image pull policy for the container. One of: Always, IfNotPresent, Never.")
+	cmd.Flags().StringArrayVar(&containerOpts.volumeMounts, "volume-mount", nil, "Volume to mount in the container, as VOLUME-NAME:MOUNT-PATH[:ro]. Can be repeated.")
+
+	cmd.Flags().StringVar(&pipelineOpts.serviceAccount, "service-account", "", "Service account the pipeline runs as.")
+	cmd.Flags().StringArrayVar(&pipelineOpts.volumes, "volume", nil, "Volume for the pipeline, as NAME:emptyDir, NAME:secret:SECRET-NAME or NAME:configMap:CONFIGMAP-NAME. Can be repeated.")
+	cmd.Flags().StringArrayVar(&pipelineOpts.permissions, "rbac", nil, "Permission for the pipeline, as \"apiGroups=apps,resources=deployments,verbs=get;list\". Can be repeated.")
+}
+
+func (o containerOptions) apply(container *v1alpha1.Container) error {
+	for _, env := range o.env {
+		if name, ok := strings.CutSuffix(env, "-"); ok && !strings.Contains(env, "=") {
+			container.Env = removeEnvVar(container.Env, name)
+			continue
+		}
+		name, value, ok := strings.Cut(env, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --env value %q, expected KEY=VALUE", env)
+		}
+		container.Env = setEnvVar(container.Env, corev1.EnvVar{Name: name, Value: value})
+	}
+
+	for _, env := range o.envFromSecret {
+		secretRef, name, ok := strings.Cut(env, "=")
+		secretName, secretKey, refOK := strings.Cut(secretRef, ":")
+		if !ok || !refOK || name == "" || secretName == "" || secretKey == "" {
+			return fmt.Errorf("invalid --env-from-secret value %q, expected SECRET-NAME:SECRET-KEY=ENV-NAME", env)
+		}
+		container.Env = setEnvVar(container.Env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  secretKey,
+				},
+			},
+		})
+	}
+
+	if o.command != "" {
+		command, err := containerutils.SplitArgs(o.command)
+		if err != nil {
+			return fmt.Errorf("invalid --command: %w", err)
+		}
+		container.Command = command
+	}
+
+	if o.args != "" {
+		args, err := containerutils.SplitArgs(o.args)
+		if err != nil {
+			return fmt.Errorf("invalid --args: %w", err)
+		}
+		container.Args = args
+	}
+
+	if o.imagePullPolicy != "" {
+		policy := corev1.PullPolicy(o.imagePullPolicy)
+		if !slices.Contains([]corev1.PullPolicy{corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever}, policy) {
+			return fmt.Errorf("invalid --image-pull-policy: %s, expected one of: Always, IfNotPresent, Never", o.imagePullPolicy)
+		}
+		container.ImagePullPolicy = policy
+	}
+
+	for _, mount := range o.volumeMounts {
+		if name, ok := strings.CutSuffix(mount, "-"); ok && !strings.Contains(mount, ":") {
+			container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool { return m.Name == name })
+			continue
+		}
+		parts := strings.Split(mount, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" || (len(parts) == 3 && parts[2] != "ro") {
+			return fmt.Errorf("invalid --volume-mount value %q, expected VOLUME-NAME:MOUNT-PATH[:ro]", mount)
+		}
+		volumeMount := corev1.VolumeMount{Name: parts[0], MountPath: parts[1], ReadOnly: len(parts) == 3}
+		idx := slices.IndexFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool { return m.Name == volumeMount.Name })
+		if idx == -1 {
+			container.VolumeMounts = append(container.VolumeMounts, volumeMount)
+		} else {
+			container.VolumeMounts[idx] = volumeMount
+		}
+	}
+	return nil
+}
+
+func (o pipelineOptions) isSet() bool {
+	return o.serviceAccount != "" || len(o.volumes) > 0 || len(o.permissions) > 0
+}
+
+func (o pipelineOptions) apply(pipeline *v1alpha1.Pipeline) error {
+	if o.serviceAccount != "" {
+		pipeline.Spec.RBAC.ServiceAccount = o.serviceAccount
+	}
+
+	for _, v := range o.volumes {
+		if name, ok := strings.CutSuffix(v, "-"); ok && !strings.Contains(v, ":") {
+			pipeline.Spec.Volumes = slices.DeleteFunc(pipeline.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == name })
+			continue
+		}
+		volume, err := parseVolume(v)
+		if err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(pipeline.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == volume.Name })
+		if idx == -1 {
+			pipeline.Spec.Volumes = append(pipeline.Spec.Volumes, volume)
+		} else {
+			pipeline.Spec.Volumes[idx] = volume
+		}
+	}
+
+	for _, p := range o.permissions {
+		permission, err := parsePermission(p)
+		if err != nil {
+			return err
+		}
+		pipeline.Spec.RBAC.Permissions = appendPermission(pipeline.Spec.RBAC.Permissions, permission)
+	}
+	return nil
+}
+
+// appendPermission appends permission unless it is already in permissions.
+func appendPermission(permissions []v1alpha1.Permission, permission v1alpha1.Permission) []v1alpha1.Permission {
+	if slices.ContainsFunc(permissions, func(p v1alpha1.Permission) bool { return formatPermission(p) == formatPermission(permission) }) {
+		return permissions
+	}
+	return append(permissions, permission)
+}
+
+func parseVolume(v string) (corev1.Volume, error) {
+	parts := strings.Split(v, ":")
+	invalid := fmt.Errorf("invalid --volume value %q, expected NAME:emptyDir, NAME:secret:SECRET-NAME or NAME:configMap:CONFIGMAP-NAME", v)
+	if len(parts) < 2 || parts[0] == "" {
+		return corev1.Volume{}, invalid
+	}
+
+	volume := corev1.Volume{Name: parts[0]}
+	switch {
+	case parts[1] == "emptyDir" && len(parts) == 2:
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	case parts[1] == "secret" && len(parts) == 3 && parts[2] != "":
+		volume.Secret = &corev1.SecretVolumeSource{SecretName: parts[2]}
+	case parts[1] == "configMap" && len(parts) == 3 && parts[2] != "":
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: parts[2]}}
+	default:
+		return corev1.Volume{}, invalid
+	}
+	return volume, nil
+}
+
+func setEnvVar(envs []corev1.EnvVar, env corev1.EnvVar) []corev1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == env.Name {
+			envs[i] = env
+			return envs
+		}
+	}
+	return append(envs, env)
+}
+
+func removeEnvVar(envs []corev1.EnvVar, name string) []corev1.EnvVar {
+	return slices.DeleteFunc(envs, func(e corev1.EnvVar) bool { return e.Name == name })
+}
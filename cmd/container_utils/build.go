@@ -22,14 +22,26 @@ type BuildContainerOptions struct {
 func ForkBuilderCommand(opts *BuildContainerOptions, containerImage, pipelineDir, containerName string) error {
 	buildCommand := "build"
 
-	buildArgs := []string{"--tag", containerImage, filepath.Join(pipelineDir, containerName)}
+	containerDir := filepath.Join(pipelineDir, containerName)
+	buildConfig, err := LoadBuildConfig(containerDir)
+	if err != nil {
+		return err
+	}
+
+	extraArgs, err := SplitArgs(opts.BuildArgs)
+	if err != nil {
+		return err
+	}
+
+	buildArgs := []string{"--tag", containerImage, buildConfig.contextDir(containerDir)}
+	buildArgs = append(buildArgs, buildConfig.builderArgs(containerDir)...)
 	if opts.Buildx {
 		buildCommand = "buildx build"
 		if opts.Push {
 			buildArgs = append(buildArgs, "--push")
 		}
 	}
-	buildArgs = append(buildArgs, strings.Fields(opts.BuildArgs)...)
+	buildArgs = append(buildArgs, extraArgs...)
 	buildArgs = append(strings.Fields(buildCommand), buildArgs...)
 
 	builder := exec.Command(opts.Engine, buildArgs...)
@@ -38,29 +50,39 @@ func ForkBuilderCommand(opts *BuildContainerOptions, containerImage, pipelineDir
 	return builder.Run()
 }
 
-func ForkRunCommand(opts *BuildContainerOptions, containerImage, inputVolume, outputVolume, metadataVolume string, envvars []string, command string) error {
-	args := []string{
+// ForkRunCommand runs containerImage with the Kratix input, output and
+// metadata directories mounted. When command is set it replaces the image
+// entrypoint; args are passed to the entrypoint.
+func ForkRunCommand(opts *BuildContainerOptions, containerImage, inputVolume, outputVolume, metadataVolume string, envvars []string, command, args []string) error {
+	runArgs := []string{
 		"run",
+		"--rm",
 		"--volume", fmt.Sprintf("%s:/kratix/input/", inputVolume),
 		"--volume", fmt.Sprintf("%s:/kratix/output/", outputVolume),
 		"--volume", fmt.Sprintf("%s:/kratix/metadata/", metadataVolume),
 	}
 
-	if len(envvars) > 0 {
-		for _, evar := range envvars {
-			args = append(args, "--env")
-			args = append(args, evar)
-		}
+	for _, evar := range envvars {
+		runArgs = append(runArgs, "--env", evar)
+	}
+
+	if len(command) > 0 {
+		runArgs = append(runArgs, "--entrypoint", command[0])
 	}
 
-	args = append(args, strings.Fields(opts.BuildArgs)...)
-	args = append(args, containerImage)
+	extraArgs, err := SplitArgs(opts.BuildArgs)
+	if err != nil {
+		return err
+	}
 
-	if command != "" {
-		args = append(args, "sh", "-c", command)
+	runArgs = append(runArgs, extraArgs...)
+	runArgs = append(runArgs, containerImage)
+	if len(command) > 1 {
+		runArgs = append(runArgs, command[1:]...)
 	}
+	runArgs = append(runArgs, args...)
 
-	cmd := exec.Command(opts.Engine, args...)
+	cmd := exec.Command(opts.Engine, runArgs...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
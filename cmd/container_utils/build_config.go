@@ -0,0 +1,124 @@
+package containerutils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const BuildConfigFileName = "build.yaml"
+
+// BuildConfig holds the optional per-container build settings read from a
+// build.yaml file placed alongside the container's Dockerfile.
+type BuildConfig struct {
+	Platforms []string          `json:"platforms,omitempty"`
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+	Target    string            `json:"target,omitempty"`
+	Secrets   []string          `json:"secrets,omitempty"`
+	Context   string            `json:"context,omitempty"`
+}
+
+// LoadBuildConfig reads the build.yaml in containerDir. It returns an empty
+// config when the file does not exist.
+func LoadBuildConfig(containerDir string) (*BuildConfig, error) {
+	config := &BuildConfig{}
+	configPath := filepath.Join(containerDir, BuildConfigFileName)
+	configBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	if err := yaml.UnmarshalStrict(configBytes, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	return config, nil
+}
+
+// contextDir returns the build context for the container; relative context
+// paths are resolved against the container directory.
+func (c *BuildConfig) contextDir(containerDir string) string {
+	if c.Context == "" {
+		return containerDir
+	}
+	if filepath.IsAbs(c.Context) {
+		return c.Context
+	}
+	return filepath.Join(containerDir, c.Context)
+}
+
+func (c *BuildConfig) builderArgs(containerDir string) []string {
+	var args []string
+	if c.Context != "" {
+		args = append(args, "--file", filepath.Join(containerDir, "Dockerfile"))
+	}
+
+	if len(c.Platforms) > 0 {
+		args = append(args, "--platform", strings.Join(c.Platforms, ","))
+	}
+
+	buildArgKeys := make([]string, 0, len(c.BuildArgs))
+	for key := range c.BuildArgs {
+		buildArgKeys = append(buildArgKeys, key)
+	}
+	slices.Sort(buildArgKeys)
+	for _, key := range buildArgKeys {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, c.BuildArgs[key]))
+	}
+
+	if c.Target != "" {
+		args = append(args, "--target", c.Target)
+	}
+
+	for _, secret := range c.Secrets {
+		args = append(args, "--secret", secret)
+	}
+	return args
+}
+
+// SplitArgs splits a command line string into arguments, honouring single
+// and double quotes so that quoted values containing spaces stay together.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in arguments: %s", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+// fileWriter makes every change commands make to a Promise directory. With
+// --dry-run nothing is changed and each change is printed instead; --diff
+// also prints a unified diff of each file against its current contents.
+//
+// Files written during a dry run are kept in memory, so that commands which
+// read back a file they wrote see the pending contents.
+type fileWriter struct {
+	dryRun   bool
+	diff     bool
+	out      io.Writer
+	pending  map[string][]byte
+	recorded map[string][]byte
+}
+
+var promiseFiles = &fileWriter{out: os.Stdout, pending: map[string][]byte{}}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&promiseFiles.dryRun, "dry-run", false, "Print the files that would be created, changed or deleted without changing them.")
+	rootCmd.PersistentFlags().BoolVar(&promiseFiles.diff, "diff", false, "Print a unified diff of every file that would change. Implies --dry-run.")
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		if promiseFiles.skipWrites() {
+			fmt.Fprintln(promiseFiles.out, "Dry run: no files were changed")
+		}
+	}
+}
+
+// skipWrites returns true when changes are only printed
+func (w *fileWriter) skipWrites() bool {
+	return w.dryRun || w.diff
+}
+
+// record starts keeping the contents of every file written, until
+// stopRecording returns them.
+func (w *fileWriter) record() {
+	w.recorded = map[string][]byte{}
+}
+
+func (w *fileWriter) stopRecording() map[string][]byte {
+	recorded := w.recorded
+	w.recorded = nil
+	return recorded
+}
+
+// writeFile writes contents to path, creating its parent directories.
+func (w *fileWriter) writeFile(path string, contents []byte) error {
+	if w.recorded != nil {
+		w.recorded[filepath.Clean(path)] = contents
+	}
+	if !w.skipWrites() {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		return os.WriteFile(path, contents, filePerm)
+	}
+
+	current, err := w.readFile(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	w.pending[filepath.Clean(path)] = contents
+
+	switch {
+	case !exists:
+		fmt.Fprintf(w.out, "Would create %s\n", path)
+	case bytes.Equal(current, contents):
+		return nil
+	default:
+		fmt.Fprintf(w.out, "Would update %s\n", path)
+	}
+
+	if w.diff {
+		fromFile := path
+		if !exists {
+			fromFile = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			B:        difflib.SplitLines(string(contents)),
+			FromFile: fromFile,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w.out, diff)
+	}
+	return nil
+}
+
+// readFile returns the contents of path, including changes pending in a dry
+// run.
+func (w *fileWriter) readFile(path string) ([]byte, error) {
+	if contents, ok := w.pending[filepath.Clean(path)]; ok {
+		return contents, nil
+	}
+	return os.ReadFile(path)
+}
+
+// exists returns true if path exists, or is pending creation in a dry run.
+func (w *fileWriter) exists(path string) bool {
+	if _, ok := w.pending[filepath.Clean(path)]; ok {
+		return true
+	}
+	return pathExists(path)
+}
+
+func (w *fileWriter) mkdirAll(path string) error {
+	if !w.skipWrites() {
+		return os.MkdirAll(path, os.ModePerm)
+	}
+	if !pathExists(path) {
+		fmt.Fprintf(w.out, "Would create %s%c\n", path, filepath.Separator)
+	}
+	return nil
+}
+
+func (w *fileWriter) removeAll(path string) error {
+	if !w.skipWrites() {
+		return os.RemoveAll(path)
+	}
+	if pathExists(path) {
+		fmt.Fprintf(w.out, "Would delete %s\n", path)
+	}
+	return nil
+}
+
+func (w *fileWriter) rename(from, to string) error {
+	if !w.skipWrites() {
+		if err := os.MkdirAll(filepath.Dir(to), os.ModePerm); err != nil {
+			return err
+		}
+		return os.Rename(from, to)
+	}
+	fmt.Fprintf(w.out, "Would move %s to %s\n", from, to)
+	return nil
+}
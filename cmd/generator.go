@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	generatorDirName      = ".kratix"
+	generatorFileName     = "generator.yaml"
+	generatedFilesDirName = "generated"
+)
+
+// generatorConfig is the init command a Promise was generated with, kept in
+// .kratix/generator.yaml so that `kratix regenerate` can run it again.
+type generatorConfig struct {
+	Command string              `json:"command"`
+	Args    []string            `json:"args,omitempty"`
+	Flags   map[string][]string `json:"flags,omitempty"`
+}
+
+// generatorIgnoredFlags only change how a Promise is written, not what is
+// generated, so they are not recorded.
+var generatorIgnoredFlags = map[string]bool{
+	"dir": true, "dry-run": true, "diff": true, "force": true, "merge": true, "help": true, "interactive": true,
+}
+
+// newGeneratorConfig records the command, arguments and flags an init
+// subcommand was run with.
+func newGeneratorConfig(cmd *cobra.Command, args []string) generatorConfig {
+	config := generatorConfig{
+		Command: strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Args:    args,
+		Flags:   map[string][]string{},
+	}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed || generatorIgnoredFlags[f.Name] {
+			return
+		}
+		if values, ok := f.Value.(pflag.SliceValue); ok {
+			config.Flags[f.Name] = values.GetSlice()
+			return
+		}
+		config.Flags[f.Name] = []string{f.Value.String()}
+	})
+	return config
+}
+
+// commandLine returns the flags of the config as command line arguments, in
+// a stable order.
+func (c generatorConfig) commandLine() []string {
+	names := make([]string, 0, len(c.Flags))
+	for name := range c.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []string
+	for _, name := range names {
+		for _, value := range c.Flags[name] {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	return args
+}
+
+func loadGeneratorConfig(promiseDir string) (generatorConfig, error) {
+	var config generatorConfig
+	configPath := filepath.Join(promiseDir, generatorDirName, generatorFileName)
+	configBytes, err := promiseFiles.readFile(configPath)
+	if err != nil {
+		return config, fmt.Errorf("failed to read %s; only Promises generated with `kratix init` can be regenerated: %w", configPath, err)
+	}
+	if err := yaml.Unmarshal(configBytes, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	return config, nil
+}
+
+// writeGenerator writes the generator config, and the files it generated
+// into .kratix/generated. Those are the base `kratix regenerate` merges
+// local changes and newly generated files against.
+func writeGenerator(promiseDir string, config generatorConfig, generated map[string][]byte) error {
+	generatorDir := filepath.Join(promiseDir, generatorDirName)
+	configBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := promiseFiles.writeFile(filepath.Join(generatorDir, generatorFileName), configBytes); err != nil {
+		return err
+	}
+
+	generatedDir := filepath.Join(generatorDir, generatedFilesDirName)
+	keep := map[string]bool{}
+	for path, contents := range generated {
+		relPath, err := filepath.Rel(promiseDir, path)
+		if err != nil || strings.HasPrefix(relPath, "..") || strings.HasPrefix(relPath, generatorDirName) {
+			continue
+		}
+		keep[relPath] = true
+		if err := promiseFiles.writeFile(filepath.Join(generatedDir, relPath), contents); err != nil {
+			return err
+		}
+	}
+
+	// files that are no longer generated
+	err = filepath.WalkDir(generatedDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(generatedDir, path)
+		if err != nil || keep[relPath] {
+			return err
+		}
+		return promiseFiles.removeAll(path)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -21,10 +21,66 @@ func init() {
 	initCmd.PersistentFlags().StringVarP(&group, "group", "g", "", "The API group for the Promise")
 	initCmd.PersistentFlags().StringVarP(&kind, "kind", "k", "", "The kind to be provided by the Promise")
 	initCmd.PersistentFlags().StringVarP(&version, "version", "v", "", "The group version for the Promise. Defaults to v1alpha1")
-	initCmd.PersistentFlags().StringVar(&plural, "plural", "", "The plural form of the kind. Defaults to the kind name with an additional 's' at the end.")
+	initCmd.PersistentFlags().StringVar(&plural, "plural", "", "The plural form of the kind. Defaults to the lowercase English plural of the kind, e.g. policies for Policy.")
 	initCmd.PersistentFlags().StringVarP(&outputDir, "dir", "d", ".", "The output directory to write the Promise structure to; defaults to '.'")
 	initCmd.PersistentFlags().BoolVar(&split, "split", false, "Split promise.yaml file into multiple files.")
 
 	initCmd.MarkPersistentFlagRequired("group")
 	initCmd.MarkPersistentFlagRequired("kind")
+
+	initCmd.PersistentPreRunE = initPreRun
+	initCmd.PersistentPostRunE = initPostRun
+}
+
+func initPreRun(cmd *cobra.Command, args []string) error {
+	if err := promptForMissingInitFlags(cmd, args); err != nil {
+		return err
+	}
+	if err := validateInitFlags(cmd, args); err != nil {
+		return err
+	}
+	if cmd == initCmd {
+		return nil
+	}
+	if err := checkExistingPromise(outputDir); err != nil {
+		return err
+	}
+	promiseFiles.record()
+	return nil
+}
+
+func initPostRun(cmd *cobra.Command, args []string) error {
+	if err := completeInit(cmd, args); err != nil {
+		return err
+	}
+	rootCmd.PersistentPostRun(cmd, args)
+	return nil
+}
+
+// completeInit records how the Promise was generated, then puts back what
+// --merge keeps of the Promise that was in --dir.
+func completeInit(cmd *cobra.Command, args []string) error {
+	generated := promiseFiles.stopRecording()
+	if generated == nil {
+		return nil
+	}
+	if err := writeGenerator(outputDir, newGeneratorConfig(cmd, args), generated); err != nil {
+		return err
+	}
+	return mergeExistingPromise()
+}
+
+// validateInitFlags checks the Promise name and API flags given to an init
+// subcommand before any file is written. Flags that are not set are left to
+// the required flag checks and the defaults.
+func validateInitFlags(cmd *cobra.Command, args []string) error {
+	if cmd == initCmd {
+		return nil
+	}
+	if len(args) > 0 {
+		if err := validatePromiseName(args[0]); err != nil {
+			return err
+		}
+	}
+	return validateAPIValues(group, kind, version, plural)
 }
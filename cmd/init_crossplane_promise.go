@@ -66,7 +66,10 @@ func InitCrossplanePromise(cmd *cobra.Command, args []string) error {
 	printPreviewWarning()
 	promiseName := args[0]
 	if plural == "" {
-		plural = fmt.Sprintf("%ss", strings.ToLower(kind))
+		var err error
+		if plural, err = pluralize(kind); err != nil {
+			return err
+		}
 	}
 
 	xrd, err := getXRD(xrdPath)
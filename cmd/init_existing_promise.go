@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+var forceInit, mergeInit bool
+
+// existingPromise holds the parts of a Promise that --merge keeps when the
+// Promise is generated again
+type existingPromise struct {
+	dir     string
+	files   map[string][]byte
+	promise *v1alpha1.Promise
+}
+
+// mergePreservedPaths are kept as they are by --merge; every file under a
+// preserved directory is kept.
+var mergePreservedPaths = []string{"README.md", dependenciesFileName, "workflows"}
+
+var promiseToMerge *existingPromise
+
+func init() {
+	initCmd.PersistentFlags().BoolVar(&forceInit, "force", false, "Overwrite the Promise in --dir if there is one")
+	initCmd.PersistentFlags().BoolVar(&mergeInit, "merge", false, "Regenerate the API of the Promise in --dir, keeping its workflows, dependencies and README")
+}
+
+// checkExistingPromise refuses to generate a Promise on top of an existing
+// one unless --force or --merge is set. With --merge, the parts of the
+// existing Promise to keep are read before anything is written.
+func checkExistingPromise(promiseDir string) error {
+	promiseToMerge = nil
+	if forceInit && mergeInit {
+		return fmt.Errorf("--force and --merge cannot be used together")
+	}
+
+	if !pathExists(filepath.Join(promiseDir, promiseFileName)) && !pathExists(filepath.Join(promiseDir, apiFileName)) {
+		return nil
+	}
+	if forceInit {
+		return nil
+	}
+	if !mergeInit {
+		return fmt.Errorf("a Promise already exists in %s; pass --merge to regenerate its API and keep its workflows, dependencies and README, or --force to overwrite it", promiseDir)
+	}
+
+	existing := &existingPromise{dir: promiseDir, files: map[string][]byte{}}
+	for _, path := range mergePreservedPaths {
+		err := filepath.WalkDir(filepath.Join(promiseDir, path), func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			contents, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			existing.files[filePath] = contents
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if promiseBytes, err := os.ReadFile(filepath.Join(promiseDir, promiseFileName)); err == nil {
+		existing.promise = &v1alpha1.Promise{}
+		if err := yaml.Unmarshal(promiseBytes, existing.promise); err != nil {
+			return fmt.Errorf("failed to parse the existing %s: %w", promiseFileName, err)
+		}
+	}
+
+	promiseToMerge = existing
+	return nil
+}
+
+// mergeExistingPromise puts back the parts of the existing Promise that
+// --merge keeps, once the new Promise has been generated.
+func mergeExistingPromise() error {
+	existing := promiseToMerge
+	if existing == nil {
+		return nil
+	}
+	promiseToMerge = nil
+
+	for path, contents := range existing.files {
+		if err := promiseFiles.writeFile(path, contents); err != nil {
+			return err
+		}
+	}
+
+	promisePath := filepath.Join(existing.dir, promiseFileName)
+	if existing.promise != nil && pathExists(promisePath) {
+		if err := mergePromiseSpec(promisePath, existing.promise); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Kept the workflows, dependencies and README of the existing Promise in %s\n", existing.dir)
+	return nil
+}
+
+// mergePromiseSpec keeps the workflows, dependencies and destination
+// selectors of the existing Promise in the newly generated promise.yaml.
+func mergePromiseSpec(promisePath string, existing *v1alpha1.Promise) error {
+	promiseBytes, err := promiseFiles.readFile(promisePath)
+	if err != nil {
+		return err
+	}
+	var promise v1alpha1.Promise
+	if err := yaml.Unmarshal(promiseBytes, &promise); err != nil {
+		return err
+	}
+	// workflows the existing Promise does not have are taken from the new one
+	oldWorkflows, newWorkflows := existing.Spec.Workflows, &promise.Spec.Workflows
+	for _, pipelines := range []struct{ old, new *[]unstructured.Unstructured }{
+		{&oldWorkflows.Resource.Configure, &newWorkflows.Resource.Configure},
+		{&oldWorkflows.Resource.Delete, &newWorkflows.Resource.Delete},
+		{&oldWorkflows.Promise.Configure, &newWorkflows.Promise.Configure},
+		{&oldWorkflows.Promise.Delete, &newWorkflows.Promise.Delete},
+	} {
+		if len(*pipelines.old) > 0 {
+			*pipelines.new = *pipelines.old
+		}
+	}
+	if len(existing.Spec.Dependencies) > 0 {
+		promise.Spec.Dependencies = existing.Spec.Dependencies
+	}
+	if len(existing.Spec.DestinationSelectors) > 0 {
+		promise.Spec.DestinationSelectors = existing.Spec.DestinationSelectors
+	}
+
+	if promiseBytes, err = yaml.Marshal(promise); err != nil {
+		return err
+	}
+	return promiseFiles.writeFile(promisePath, promiseBytes)
+}
@@ -2,55 +2,181 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
 	helmclient "github.com/mittwald/go-helm-client"
 	"github.com/spf13/cobra"
 	"github.com/syntasso/kratix-cli/internal"
 	"github.com/syntasso/kratix/api/v1alpha1"
-	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/yaml"
 )
 
 var intHelmPromiseCmd = &cobra.Command{
-	Use:   "helm-promise PROMISE-NAME --chart-url HELM-CHART-URL --group PROMISE-API-GROUP --kind PROMISE-API-KIND [--chart-version]",
+	Use:   "helm-promise PROMISE-NAME (--chart-url HELM-CHART-URL | --chart-path HELM-CHART-PATH) --group PROMISE-API-GROUP --kind PROMISE-API-KIND [--chart-version]",
 	Short: "Preview: Initialize a new Promise from a Helm chart",
 	Long: "Preview: Initialize a new Promise from a Helm Chart. " +
 		"This command is in preview, not supported under SLAs, and may change or break without notice.",
 	Example: `  # initialize a new promise from an OCI Helm Chart
-  kratix init helm-promise jenkins --chart-url oci://ghcr.io/jenkinsci/helm-charts/jenkins [--chart-version] --group syntasso.io --kind cicd
+  kratix init helm-promise jenkins --chart-url oci://ghcr.io/jenkinsci/helm-charts/jenkins [--chart-version] --group syntasso.io --kind Cicd
 
   # initialize a new promise from a Helm Chart repository
-  kratix init helm-promise postgresql --chart-url https://fluxcd-community.github.io/helm-charts --chart-name flux2 [--chart-version] --group syntasso.io --kind database
+  kratix init helm-promise postgresql --chart-url https://fluxcd-community.github.io/helm-charts --chart-name flux2 [--chart-version] --group syntasso.io --kind Database
 
   # initialize a new promise from a Helm Chart tar URL
-  kratix init helm-promise postgresql --chart-url https://github.com/stefanprodan/podinfo/raw/gh-pages/podinfo-0.2.1.tgz --group syntasso.io --kind database
+  kratix init helm-promise postgresql --chart-url https://github.com/stefanprodan/podinfo/raw/gh-pages/podinfo-0.2.1.tgz --group syntasso.io --kind Database
+
+  # initialize a new promise from a local chart directory or packaged chart, vendored into the pipeline image
+  kratix init helm-promise web --chart-path ./charts/web --group syntasso.io --kind Web
+
+  # only expose some of the chart's values, and set others in the pipeline
+  kratix init helm-promise redis --chart-url oci://registry-1.docker.io/bitnamicharts/redis --group syntasso.io --kind Redis --include-values 'image.tag,replica.*' --fixed-values fixed-values.yaml
 `,
 	RunE: InitHelmPromise,
 	Args: cobra.ExactArgs(1),
 }
 
-var chartURL, chartName, chartVersion string
+const (
+	helmResourceConfigureImage = "ghcr.io/syntasso/kratix-cli/helm-resource-configure"
+	// helmResourceConfigureVersion is the first helm-promise stage release
+	// built from stages/helm-promise/main.go; earlier releases ignore every
+	// environment variable other than CHART_URL, CHART_NAME, CHART_VERSION and
+	// TARGET_NAMESPACE.
+	helmResourceConfigureVersion = "v0.4.0"
+	// vendoredChartImage is the image built from helmResourceConfigureImage
+	// and the chart vendored with --vendor-chart.
+	vendoredChartImage = "my-registry.io/my-org/kratix/helm-resource-configure:v0.0.1"
+	// vendoredChartDir is where the vendored chart is in the image
+	vendoredChartDir = "/resources/"
+)
+
+var (
+	chartURL, chartName, chartVersion string
+	chartPath                         string
+	vendorChart                       bool
+	includeValues, excludeValues      []string
+	fixedValuesFile                   string
+
+	crdsAsDependencies, clusterResourcesAsDependencies bool
+
+	targetNamespace, releaseNameTemplate string
+	namespaceFromRequest                 bool
+
+	helmAuth          internal.HelmAuthOptions
+	passwordStdin     bool
+	credentialsSecret string
+)
+
+// helmCredentialsDir is where the pipeline mounts the --credentials-secret.
+const helmCredentialsDir = "/kratix/helm-credentials"
 
 func init() {
 	initCmd.AddCommand(intHelmPromiseCmd)
 	intHelmPromiseCmd.Flags().StringVarP(&chartURL, "chart-url", "", "", "The URL (supports OCI and tarball) of the Helm chart")
 	intHelmPromiseCmd.Flags().StringVarP(&chartVersion, "chart-version", "", "", "The Helm chart version. Default to latest")
 	intHelmPromiseCmd.Flags().StringVarP(&chartName, "chart-name", "", "", "The Helm chart name. Required when using Helm repository")
-	intHelmPromiseCmd.MarkFlagRequired("chart-url")
+	intHelmPromiseCmd.Flags().StringVar(&chartPath, "chart-path", "", "Path of a local chart directory or packaged .tgz chart. The chart is vendored into the pipeline image, as with --vendor-chart")
+	intHelmPromiseCmd.Flags().BoolVar(&vendorChart, "vendor-chart", false, "Package the chart into the resources/ directory of the resource configure container, so the pipeline does not fetch it")
+	intHelmPromiseCmd.Flags().StringSliceVar(&includeValues, "include-values", nil, "Comma separated dotted paths of the chart values to expose in the Promise API, e.g. 'image.tag,persistence.*'. Defaults to all values")
+	intHelmPromiseCmd.Flags().StringSliceVar(&excludeValues, "exclude-values", nil, "Comma separated dotted paths of the chart values to leave out of the Promise API, e.g. 'ingress.*'")
+	intHelmPromiseCmd.Flags().StringVar(&fixedValuesFile, "fixed-values", "", "Path to a values file set by the Promise pipeline for every request. Its values are not exposed in the Promise API")
+	intHelmPromiseCmd.Flags().BoolVar(&crdsAsDependencies, "crds-as-dependencies", false, "Install the chart's CRDs once per Destination as Promise dependencies, instead of with every request")
+	intHelmPromiseCmd.Flags().BoolVar(&clusterResourcesAsDependencies, "cluster-resources-as-dependencies", false, "Install every cluster scoped resource of the chart, such as CRDs, ClusterRoles and webhook configurations, once per Destination as Promise dependencies. Templated resources are rendered once, with the Promise name as release name")
+	intHelmPromiseCmd.Flags().StringVar(&targetNamespace, "target-namespace", "", "The namespace to release the chart in. Defaults to 'default'")
+	intHelmPromiseCmd.Flags().BoolVar(&namespaceFromRequest, "namespace-from-request", false, "Release the chart in the namespace of each request")
+	intHelmPromiseCmd.Flags().StringVar(&releaseNameTemplate, "release-name-template", "", "Go template rendered with each request to name its release, e.g. '{{ .metadata.namespace }}-{{ .metadata.name }}'. Defaults to the request name")
+	intHelmPromiseCmd.Flags().StringVar(&credentialsSecret, "credentials-secret", "", "Name of a Secret the pipeline fetches the chart with, holding any of the keys username, password, ca.crt, tls.crt, tls.key and .dockerconfigjson")
+	intHelmPromiseCmd.Flags().BoolVar(&withDelete, "with-delete", false, withDeleteFlagUsage)
+	addHelmAuthFlags(intHelmPromiseCmd)
+	intHelmPromiseCmd.MarkFlagsOneRequired("chart-url", "chart-path")
+	intHelmPromiseCmd.MarkFlagsMutuallyExclusive("chart-url", "chart-path")
+	intHelmPromiseCmd.MarkFlagsMutuallyExclusive("chart-path", "chart-name")
+	intHelmPromiseCmd.MarkFlagsMutuallyExclusive("chart-path", "chart-version")
+	intHelmPromiseCmd.MarkFlagsMutuallyExclusive("target-namespace", "namespace-from-request")
+}
+
+// addHelmAuthFlags adds the flags to fetch charts from private chart
+// repositories and OCI registries with.
+func addHelmAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&helmAuth.Username, "username", "", "Username for the chart repository or OCI registry")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "Read the password for the chart repository or OCI registry from stdin")
+	cmd.Flags().StringVar(&helmAuth.CAFile, "ca-file", "", "Verify the certificate of the chart repository or OCI registry with this CA bundle")
+	cmd.Flags().StringVar(&helmAuth.CertFile, "cert-file", "", "Identify to the chart repository or OCI registry with this TLS client certificate")
+	cmd.Flags().StringVar(&helmAuth.KeyFile, "key-file", "", "Identify to the chart repository or OCI registry with this TLS client key")
+	cmd.Flags().BoolVar(&helmAuth.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Skip the TLS certificate checks of the chart repository or OCI registry")
+	cmd.Flags().StringVar(&helmAuth.RegistryConfig, "registry-config", "", "Path to the OCI registry config file, as written by 'helm registry login'. Defaults to Helm's")
+	cmd.MarkFlagsRequiredTogether("username", "password-stdin")
+	cmd.MarkFlagsRequiredTogether("cert-file", "key-file")
+}
+
+// readPasswordStdin reads the --password-stdin password.
+func readPasswordStdin() error {
+	if !passwordStdin {
+		return nil
+	}
+	password, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	helmAuth.Password = strings.TrimRight(string(password), "\r\n")
+	return nil
 }
 
 func InitHelmPromise(cmd *cobra.Command, args []string) error {
 	printPreviewWarning()
 	promiseName := args[0]
-	resourceConfigure, err := generateHelmResourceConfigurePipeline()
+	if err := validateReleaseOptions(); err != nil {
+		return err
+	}
+
+	fixedValuesYAML, fixedValues, err := readFixedValues()
+	if err != nil {
+		return err
+	}
+
+	if err := readPasswordStdin(); err != nil {
+		return err
+	}
+
+	// the pipeline cannot read local charts, so they are always vendored
+	if chartPath != "" {
+		vendorChart = true
+	}
+
+	helmChart, err := loadChart()
+	if err != nil {
+		return err
+	}
+
+	var vendoredChart string
+	if vendorChart {
+		if vendoredChart, err = writeVendoredChart(helmChart); err != nil {
+			return err
+		}
+	}
+
+	resourceConfigure, err := generateHelmResourceConfigurePipeline(fixedValuesYAML, vendoredChart)
+	if err != nil {
+		return err
+	}
+
+	crdSchema, err := schemaFromChart(helmChart, fixedValues)
 	if err != nil {
 		return err
 	}
 
-	crdSchema, err := schemaFromChart()
+	dependencies, err := helmChartDependencies(helmChart, promiseName, fixedValues)
 	if err != nil {
 		return err
 	}
@@ -59,6 +185,7 @@ func InitHelmPromise(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	templateValues.Dependencies = dependencies
 
 	templates := map[string]string{
 		resourceFileName: "templates/promise/example-resource.yaml.tpl",
@@ -78,17 +205,79 @@ func InitHelmPromise(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if withDelete {
+		if err := writeResourceDeleteWorkflow(promiseName, "helm"); err != nil {
+			return err
+		}
+	}
+
 	dirName := "the current directory"
 	if outputDir != "." {
 		dirName = outputDir
 	}
 
 	fmt.Printf("%s promise bootstrapped in %s\n", promiseName, dirName)
+	if vendorChart {
+		fmt.Println("The chart is vendored into the resource configure container. Run the following command to build its image:")
+		fmt.Printf("\n  kratix build container resource/configure/instance-configure --dir %s\n\n", outputDir)
+		fmt.Println("Don't forget to update the image tag in the Promise and push your image to a registry!")
+	}
 	return nil
 }
 
-func generateHelmResourceConfigurePipeline() (string, error) {
+// loadChart loads the chart from --chart-path, or fetches it from
+// --chart-url.
+func loadChart() (*chart.Chart, error) {
+	if chartPath == "" {
+		return fetchChart(chartURL, chartName, chartVersion)
+	}
+	helmChart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm chart from %s: %w", chartPath, err)
+	}
+	return helmChart, nil
+}
+
+// writeVendoredChart packages the chart into the resources/ directory of the
+// resource configure container, next to a Dockerfile adding it to the
+// helm-resource-configure image. It returns the name of the packaged chart.
+func writeVendoredChart(helmChart *chart.Chart) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "kratix-helm-chart")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	packagePath, err := chartutil.Save(helmChart, tmpDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to package helm chart: %w", err)
+	}
+	packageBytes, err := os.ReadFile(packagePath)
+	if err != nil {
+		return "", err
+	}
+
+	containerDir := workflowDir("", "resource", "configure", "instance-configure", "instance-configure")
+	packageName := filepath.Base(packagePath)
+	if err := promiseFiles.writeFile(filepath.Join(outputDir, containerDir, "resources", packageName), packageBytes); err != nil {
+		return "", err
+	}
+	templates := map[string]string{
+		filepath.Join(containerDir, "Dockerfile"): "templates/workflows/helm-promise/Dockerfile.tpl",
+	}
+	if err := templateFiles(workflowTemplates, outputDir, templates, map[string]string{"Image": helmResourceConfigureImage + ":" + helmResourceConfigureVersion}); err != nil {
+		return "", err
+	}
+	return packageName, nil
+}
+
+func generateHelmResourceConfigurePipeline(fixedValues, vendoredChart string) (string, error) {
+	image := helmResourceConfigureImage + ":" + helmResourceConfigureVersion
 	envVars := []corev1.EnvVar{{Name: "CHART_URL", Value: chartURL}}
+	if vendoredChart != "" {
+		image = vendoredChartImage
+		envVars[0].Value = vendoredChartDir + vendoredChart
+	}
 	if chartName != "" {
 		envVars = append(envVars, corev1.EnvVar{Name: "CHART_NAME", Value: chartName})
 	}
@@ -97,6 +286,48 @@ func generateHelmResourceConfigurePipeline() (string, error) {
 		envVars = append(envVars, corev1.EnvVar{Name: "CHART_VERSION", Value: chartVersion})
 	}
 
+	if fixedValues != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "FIXED_VALUES", Value: fixedValues})
+	}
+
+	if targetNamespace != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "TARGET_NAMESPACE", Value: targetNamespace})
+	}
+
+	if namespaceFromRequest {
+		envVars = append(envVars, corev1.EnvVar{Name: "NAMESPACE_FROM_REQUEST", Value: "true"})
+	}
+
+	if releaseNameTemplate != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "RELEASE_NAME_TEMPLATE", Value: releaseNameTemplate})
+	}
+
+	if clusterResourcesAsDependencies {
+		envVars = append(envVars, corev1.EnvVar{Name: "EXCLUDE_CLUSTER_RESOURCES", Value: "true"})
+	} else if crdsAsDependencies {
+		envVars = append(envVars, corev1.EnvVar{Name: "EXCLUDE_CRDS", Value: "true"})
+	}
+
+	if helmAuth.InsecureSkipTLSVerify {
+		envVars = append(envVars, corev1.EnvVar{Name: "INSECURE_SKIP_TLS_VERIFY", Value: "true"})
+	}
+
+	container := v1alpha1.Container{
+		Name:  "instance-configure",
+		Image: image,
+		Env:   envVars,
+	}
+	spec := map[string]interface{}{}
+	if credentialsSecret != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "HELM_CREDENTIALS_DIR", Value: helmCredentialsDir})
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "helm-credentials", MountPath: helmCredentialsDir, ReadOnly: true}}
+		spec["volumes"] = []corev1.Volume{{
+			Name:         "helm-credentials",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: credentialsSecret}},
+		}}
+	}
+	spec["containers"] = []interface{}{container}
+
 	pipelines := []unstructured.Unstructured{
 		{
 			Object: map[string]interface{}{
@@ -105,15 +336,7 @@ func generateHelmResourceConfigurePipeline() (string, error) {
 				"metadata": map[string]interface{}{
 					"name": "instance-configure",
 				},
-				"spec": map[string]interface{}{
-					"containers": []interface{}{
-						v1alpha1.Container{
-							Name:  "instance-configure",
-							Image: "ghcr.io/syntasso/kratix-cli/helm-resource-configure:v0.2.0",
-							Env:   envVars,
-						},
-					},
-				},
+				"spec": spec,
 			},
 		},
 	}
@@ -124,15 +347,17 @@ func generateHelmResourceConfigurePipeline() (string, error) {
 	return string(pipelineBytes), nil
 }
 
-func schemaFromChart() (string, error) {
-	values, err := valuesFromChart()
+// schemaFromChart generates the Promise API from the chart's
+// values.schema.json, or infers it from the chart's values when the chart
+// has none. The comments and values in values.yaml document the API, which
+// only exposes the values selected by --include-values and --exclude-values
+// and not set by --fixed-values.
+func schemaFromChart(helmChart *chart.Chart, fixedValues map[string]any) (string, error) {
+	schema, unmatched, err := helmValuesSchema(helmChart, fixedValues, includeValues, excludeValues)
 	if err != nil {
 		return "", err
 	}
-	schema, err := internal.HelmValuesToSchema(values)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert helm values to schema: %w", err)
-	}
+	printUnmatchedValuesPatterns(helmChart, unmatched)
 
 	bytes, err := yaml.Marshal(*schema)
 	if err != nil {
@@ -142,49 +367,267 @@ func schemaFromChart() (string, error) {
 	return string(bytes), nil
 }
 
-func valuesFromChart() (map[string]interface{}, error) {
+// helmValuesSchema returns the schema of the chart's values, and the include
+// and exclude patterns that match none of them.
+func helmValuesSchema(helmChart *chart.Chart, fixedValues map[string]any, include, exclude []string) (*apiextensionsv1.JSONSchemaProps, []string, error) {
+	var err error
+	var schema *apiextensionsv1.JSONSchemaProps
+	if len(helmChart.Schema) > 0 {
+		schema, err = internal.HelmValuesJSONSchemaToSchema(helmChart.Schema)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert the chart's values.schema.json to schema: %w", err)
+		}
+		internal.RemoveDefaultedRequired(schema, helmChart.Values)
+	} else {
+		schema, err = internal.HelmValuesToSchema(helmChart.Values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert helm values to schema: %w", err)
+		}
+	}
+
+	for _, file := range helmChart.Raw {
+		if file.Name == "values.yaml" {
+			if err := internal.AddHelmValuesDocs(schema, file.Data); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	unmatched, err := internal.FilterHelmValuesSchema(schema, include, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	internal.RemoveHelmValues(schema, fixedValues)
+	return schema, unmatched, nil
+}
+
+func printUnmatchedValuesPatterns(helmChart *chart.Chart, patterns []string) {
+	for _, pattern := range patterns {
+		fmt.Printf("warning: values pattern %q matches no value of chart %s\n", pattern, helmChart.Name())
+	}
+}
+
+// helmChartDependencies returns the chart's CRDs, or all its cluster scoped
+// resources, as Promise dependencies so they are installed once per
+// Destination. Templated resources are rendered with the chart's default and
+// fixed values, and the Promise name as release name.
+func helmChartDependencies(helmChart *chart.Chart, promiseName string, fixedValues map[string]any) (string, error) {
+	if !crdsAsDependencies && !clusterResourcesAsDependencies {
+		return "", nil
+	}
+
+	var manifests []string
+	for _, crd := range helmChart.CRDObjects() {
+		manifests = append(manifests, string(crd.File.Data))
+	}
+	crdFiles := len(manifests)
+	namespace := "default"
+	if targetNamespace != "" {
+		namespace = targetNamespace
+	}
+	rendered, err := internal.RenderHelmChart(helmChart, fixedValues, internal.HelmRenderOptions{
+		ReleaseName: promiseName,
+		Namespace:   namespace,
+	})
+	if err != nil {
+		return "", err
+	}
+	manifests = append(manifests, rendered...)
+
+	releaseDependent, err := releaseDependentManifests(helmChart, fixedValues, len(rendered))
+	if err != nil {
+		return "", err
+	}
+
+	var dependencies []v1alpha1.Dependency
+	for i, manifest := range manifests {
+		decoder := k8syaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 2048)
+		for {
+			var obj *unstructured.Unstructured
+			err := decoder.Decode(&obj)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to decode the chart's resources: %w", err)
+			}
+			if obj == nil || obj.GetKind() == "" {
+				continue
+			}
+			if obj.GetKind() == "CustomResourceDefinition" || clusterResourcesAsDependencies && internal.IsClusterScopedKind(obj.GetKind()) {
+				if i >= crdFiles && releaseDependent[i-crdFiles] {
+					fmt.Printf("warning: %s %s is rendered from the release name or namespace; the dependency installs it once for release %s in namespace %s, not once per request\n", obj.GetKind(), obj.GetName(), promiseName, namespace)
+				}
+				dependencies = append(dependencies, v1alpha1.Dependency{Unstructured: *obj})
+			}
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return "", fmt.Errorf("chart %s has no cluster scoped resources to install as dependencies", helmChart.Name())
+	}
+
+	dependenciesBytes, err := yaml.Marshal(dependencies)
+	if err != nil {
+		return "", err
+	}
+	return string(dependenciesBytes), nil
+}
+
+// releaseDependentManifests renders the chart again with another release name
+// and namespace, and returns which of its count rendered manifests change, so
+// that the resources every request would render differently can be reported.
+func releaseDependentManifests(helmChart *chart.Chart, fixedValues map[string]any, count int) ([]bool, error) {
+	const probe = "kratix-release-probe"
+	rendered, err := internal.RenderHelmChart(helmChart, fixedValues, internal.HelmRenderOptions{
+		ReleaseName: probe,
+		Namespace:   probe,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dependent := make([]bool, count)
+	for i := range dependent {
+		// templates that only render for some releases shift every later
+		// manifest, so those are all reported
+		dependent[i] = len(rendered) != count || strings.Contains(rendered[i], probe)
+	}
+	return dependent, nil
+}
+
+// validateReleaseOptions checks the namespace, release name template and
+// credentials Secret the pipeline releases the chart with.
+func validateReleaseOptions() error {
+	if targetNamespace != "" {
+		if err := validateNamespace(targetNamespace); err != nil {
+			return err
+		}
+	}
+	if credentialsSecret != "" {
+		if err := validateSecretName(credentialsSecret); err != nil {
+			return err
+		}
+	}
+	if releaseNameTemplate != "" {
+		if _, err := template.New("release-name").Funcs(sprig.TxtFuncMap()).Parse(releaseNameTemplate); err != nil {
+			return fmt.Errorf("invalid release name template %q: %w", releaseNameTemplate, err)
+		}
+	}
+	return nil
+}
+
+// readFixedValues returns the contents of the --fixed-values file and the
+// values it sets.
+func readFixedValues() (string, map[string]any, error) {
+	if fixedValuesFile == "" {
+		return "", nil, nil
+	}
+	contents, err := os.ReadFile(fixedValuesFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read fixed values: %w", err)
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(contents, &values); err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", fixedValuesFile, err)
+	}
+	return string(contents), values, nil
+}
+
+func fetchChart(url, name, version string) (*chart.Chart, error) {
 	client, err := helmclient.New(nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create helm client: %w", err)
 	}
 
-	install := action.NewInstall(&action.Configuration{})
-	registryClient, _ := registry.NewClient()
-	install.SetRegistryClient(registryClient)
+	install, err := internal.NewHelmInstall(helmAuth)
+	if err != nil {
+		return nil, err
+	}
 
-	if chartName != "" {
-		install.RepoURL = chartURL
+	if name != "" {
+		install.RepoURL = url
 	}
 
-	if chartVersion != "" {
-		install.ChartPathOptions.Version = chartVersion
+	if version != "" {
+		install.ChartPathOptions.Version = version
 	}
 
-	chart, _, err := client.GetChart(getChartName(), &install.ChartPathOptions)
+	helmChart, _, err := client.GetChart(getChartName(url, name), &install.ChartPathOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch helm chart: %w", err)
 	}
 
-	return chart.Values, nil
+	return helmChart, nil
 }
 
 // when provided --chart-url is a chart repo and --chart-name is provided, getChartName() returns chart-name
 // when provided --chart-url is OCI or a tar chart, getChartName() returns chart url
-func getChartName() string {
-	if chartName != "" {
-		return chartName
+func getChartName(url, name string) string {
+	if name != "" {
+		return name
 	}
-	return chartURL
+	return url
 }
 
 func flags() string {
 	flags := fmt.Sprintf("--chart-url %s", chartURL)
+	if chartPath != "" {
+		flags = fmt.Sprintf("--chart-path %s", chartPath)
+	}
+	if vendorChart && chartPath == "" {
+		flags += " --vendor-chart"
+	}
 	if chartName != "" {
 		flags += fmt.Sprintf(" --chart-name %s", chartName)
 	}
 	if chartVersion != "" {
 		flags += fmt.Sprintf(" --chart-version %s", chartVersion)
 	}
+	if len(includeValues) > 0 {
+		flags += fmt.Sprintf(" --include-values '%s'", strings.Join(includeValues, ","))
+	}
+	if len(excludeValues) > 0 {
+		flags += fmt.Sprintf(" --exclude-values '%s'", strings.Join(excludeValues, ","))
+	}
+	if fixedValuesFile != "" {
+		flags += fmt.Sprintf(" --fixed-values %s", fixedValuesFile)
+	}
+	if targetNamespace != "" {
+		flags += fmt.Sprintf(" --target-namespace %s", targetNamespace)
+	}
+	if namespaceFromRequest {
+		flags += " --namespace-from-request"
+	}
+	if releaseNameTemplate != "" {
+		flags += fmt.Sprintf(" --release-name-template '%s'", releaseNameTemplate)
+	}
+	if crdsAsDependencies {
+		flags += " --crds-as-dependencies"
+	}
+	if clusterResourcesAsDependencies {
+		flags += " --cluster-resources-as-dependencies"
+	}
+	if helmAuth.Username != "" {
+		flags += fmt.Sprintf(" --username %s --password-stdin", helmAuth.Username)
+	}
+	if helmAuth.CAFile != "" {
+		flags += fmt.Sprintf(" --ca-file %s", helmAuth.CAFile)
+	}
+	if helmAuth.CertFile != "" {
+		flags += fmt.Sprintf(" --cert-file %s --key-file %s", helmAuth.CertFile, helmAuth.KeyFile)
+	}
+	if helmAuth.InsecureSkipTLSVerify {
+		flags += " --insecure-skip-tls-verify"
+	}
+	if helmAuth.RegistryConfig != "" {
+		flags += fmt.Sprintf(" --registry-config %s", helmAuth.RegistryConfig)
+	}
+	if credentialsSecret != "" {
+		flags += fmt.Sprintf(" --credentials-secret %s", credentialsSecret)
+	}
+	if withDelete {
+		flags += " --with-delete"
+	}
 
 	return flags
 }
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var interactive bool
+
+// initQuestion is a flag the init wizard asks for
+type initQuestion struct {
+	flag     string
+	label    string
+	required bool
+	validate func(string) error
+}
+
+// initPromiseType is a Promise type the init wizard can generate, with the
+// questions for the flags specific to its init subcommand
+type initPromiseType struct {
+	command   *cobra.Command
+	questions []initQuestion
+}
+
+func init() {
+	initCmd.RunE = InitInteractive
+	initCmd.Flags().BoolVar(&interactive, "interactive", false, "Walk through initializing a Promise, prompting for each value")
+}
+
+func initPromiseTypes() []initPromiseType {
+	return []initPromiseType{
+		{command: initPromiseCmd},
+		{command: intHelmPromiseCmd, questions: []initQuestion{
+			{flag: "chart-url", label: "Helm chart URL (OCI, tarball or repository URL)", required: true, validate: validateChartURL},
+			{flag: "chart-name", label: "Helm chart name (only for repository URLs)"},
+			{flag: "chart-version", label: "Helm chart version (empty for latest)"},
+		}},
+		{command: terraformModuleCmd, questions: []initQuestion{
+			{flag: "module-source", label: "Terraform module source", required: true, validate: validateReachable},
+			{flag: "module-registry-version", label: "Terraform module registry version (only for registry modules)"},
+		}},
+		{command: crossplanePromiseCmd, questions: []initQuestion{
+			{flag: "xrd", label: "Path to the XRD file", required: true, validate: validatePathExists},
+			{flag: "compositions", label: "Path to the Compositions file", validate: validatePathExists},
+			{flag: "functions", label: "Path to the Functions file", validate: validatePathExists},
+		}},
+		{command: operatorPromiseCmd, questions: []initQuestion{
+			{flag: "operator-manifests", label: "Path to the operator manifests directory", required: true, validate: validatePathExists},
+			{flag: "api-schema-from", label: "Name of the CRD to generate the Promise API from", required: true},
+		}},
+		{command: pulumiComponentPromiseCmd, questions: []initQuestion{
+			{flag: "schema", label: "Path or URL of the Pulumi package schema", required: true, validate: validateReachable},
+			{flag: "component", label: "Pulumi component token (empty if the schema has a single component)"},
+		}},
+	}
+}
+
+// initCommonQuestions are asked for every Promise type, after its name
+func initCommonQuestions() []initQuestion {
+	return []initQuestion{
+		{flag: "group", label: "API group", required: true, validate: validateGroup},
+		{flag: "kind", label: "API kind", required: true, validate: validateKind},
+		{flag: "version", label: "API version (empty for v1alpha1)", validate: validateVersion},
+		{flag: "dir", label: "Output directory"},
+	}
+}
+
+// initPlan is what the init wizard will run: an init subcommand with the
+// Promise name and flags the user answered
+type initPlan struct {
+	command *cobra.Command
+	name    string
+	flags   [][2]string
+}
+
+func InitInteractive(cmd *cobra.Command, args []string) error {
+	if !interactive {
+		return cmd.Help()
+	}
+	if !stdinIsTerminal() {
+		return fmt.Errorf("--interactive requires a terminal; run one of the init subcommands with flags instead")
+	}
+
+	plan, err := askInitPlan()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nThe Promise will be generated with:\n  %s\n\n", plan.commandLine())
+
+	runAnswer, err := prompt("Run this command? [Y/n]", "y")
+	if err != nil {
+		return err
+	}
+	if !slices.Contains([]string{"y", "yes"}, strings.ToLower(runAnswer)) {
+		fmt.Println("Aborted")
+		return nil
+	}
+	return plan.run()
+}
+
+// askInitPlan walks through choosing the Promise type and answering the
+// questions for its init subcommand.
+func askInitPlan() (*initPlan, error) {
+	promiseTypes := initPromiseTypes()
+	var typeNames []string
+	for _, t := range promiseTypes {
+		typeNames = append(typeNames, t.command.Name())
+	}
+
+	typeName, err := askInitQuestion(initQuestion{
+		label:    "Promise type (" + strings.Join(typeNames, ", ") + ")",
+		required: true,
+		validate: func(answer string) error {
+			if !slices.Contains(typeNames, answer) {
+				return fmt.Errorf("unknown Promise type %q, expected one of: %s", answer, strings.Join(typeNames, ", "))
+			}
+			return nil
+		},
+	}, "promise")
+	if err != nil {
+		return nil, err
+	}
+	promiseType := promiseTypes[slices.Index(typeNames, typeName)]
+
+	plan := &initPlan{command: promiseType.command}
+	plan.name, err = askInitQuestion(initQuestion{label: "Promise name", required: true, validate: validatePromiseName}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range append(initCommonQuestions(), promiseType.questions...) {
+		defaultValue := ""
+		if flag := promiseType.command.Flag(q.flag); flag != nil && flag.Value.Type() == "string" {
+			defaultValue = flag.Value.String()
+		}
+		answer, err := askInitQuestion(q, defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		if answer != "" && answer != defaultValue {
+			plan.flags = append(plan.flags, [2]string{q.flag, answer})
+		}
+	}
+
+	splitAnswer, err := prompt("Split promise.yaml into multiple files? [y/N]", "")
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains([]string{"y", "yes"}, strings.ToLower(splitAnswer)) {
+		plan.flags = append(plan.flags, [2]string{"split", "true"})
+	}
+	return plan, nil
+}
+
+// commandLine returns the init command equivalent to the plan
+func (p *initPlan) commandLine() string {
+	commandLine := []string{"kratix", "init", p.command.Name(), p.name}
+	for _, flag := range p.flags {
+		if flag[0] == "split" {
+			commandLine = append(commandLine, "--split")
+			continue
+		}
+		commandLine = append(commandLine, "--"+flag[0], shellQuoteArg(flag[1]))
+	}
+	return strings.Join(commandLine, " ")
+}
+
+func (p *initPlan) run() error {
+	for _, flag := range p.flags {
+		if err := setInitFlag(p.command, flag[0], flag[1]); err != nil {
+			return err
+		}
+	}
+	args := []string{p.name}
+	if err := initPreRun(p.command, args); err != nil {
+		return err
+	}
+	if err := p.command.RunE(p.command, args); err != nil {
+		return err
+	}
+	return completeInit(p.command, args)
+}
+
+// askInitQuestion prompts until the answer is valid. Optional questions
+// accept an empty answer.
+func askInitQuestion(q initQuestion, defaultValue string) (string, error) {
+	for {
+		answer, err := prompt(q.label, defaultValue)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			if !q.required {
+				return "", nil
+			}
+			fmt.Println("  a value is required")
+			continue
+		}
+		if q.validate != nil {
+			if err := q.validate(answer); err != nil {
+				fmt.Printf("  %s\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// promptForMissingInitFlags asks for the required flags that were not set
+// when an init subcommand runs in a terminal, so that users don't have to
+// start again when they forget one.
+func promptForMissingInitFlags(cmd *cobra.Command, args []string) error {
+	if cmd == initCmd {
+		// the wizard asks for the group and kind itself, and `kratix init`
+		// without --interactive prints the help
+		for _, name := range []string{"group", "kind"} {
+			if err := cmd.Flags().SetAnnotation(name, cobra.BashCompOneRequiredFlag, []string{"false"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !stdinIsTerminal() {
+		return nil
+	}
+
+	validators := map[string]func(string) error{}
+	questions := initCommonQuestions()
+	for _, t := range initPromiseTypes() {
+		if t.command == cmd {
+			questions = append(questions, t.questions...)
+		}
+	}
+	for _, q := range questions {
+		validators[q.flag] = q.validate
+	}
+
+	var missing []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if required, ok := flag.Annotations[cobra.BashCompOneRequiredFlag]; ok && required[0] == "true" && !flag.Changed {
+			missing = append(missing, flag.Name)
+		}
+	})
+
+	for _, flagName := range missing {
+		answer, err := askInitQuestion(initQuestion{
+			label:    fmt.Sprintf("--%s (%s)", flagName, cmd.Flag(flagName).Usage),
+			required: true,
+			validate: validators[flagName],
+		}, "")
+		if err != nil {
+			return err
+		}
+		if err := cmd.Flags().Set(flagName, answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setInitFlag(cmd *cobra.Command, name, value string) error {
+	flag := cmd.Flag(name)
+	if flag == nil {
+		return fmt.Errorf("unknown flag --%s for %s", name, cmd.Name())
+	}
+	if err := flag.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	flag.Changed = true
+	return nil
+}
+
+func validatePathExists(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return nil
+}
+
+// validateReachable checks that a chart, module or schema location can be
+// used: local paths must exist and HTTP URLs should respond. Other sources,
+// like OCI registries and git URLs, are checked when the Promise is generated.
+func validateReachable(source string) error {
+	if isHTTPURL(source) {
+		// the module in a subdirectory or at a ref of an archive is not a URL
+		// that responds on its own
+		if strings.Contains(strings.SplitN(source, "://", 2)[1], "//") || strings.Contains(source, "?ref=") {
+			return nil
+		}
+		warnUnreachable(probeURL(source))
+		return nil
+	}
+	return validateLocalSource(source)
+}
+
+// validateChartURL checks a chart location like validateReachable, probing
+// the index of chart repositories, which do not respond on their own URL.
+func validateChartURL(source string) error {
+	if isHTTPURL(source) {
+		warnUnreachable(probeChartURL(source))
+		return nil
+	}
+	return validateLocalSource(source)
+}
+
+func validateLocalSource(source string) error {
+	if strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") {
+		return validatePathExists(source)
+	}
+	return nil
+}
+
+func isHTTPURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// warnUnreachable warns, rather than fails, when a URL does not respond, as
+// it may need credentials the init command is given later.
+func warnUnreachable(err error) {
+	if err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
+
+// probeChartURL checks that a chart tarball, or the index of a chart
+// repository, responds at url.
+func probeChartURL(url string) error {
+	err := probeURL(url)
+	if err == nil {
+		return nil
+	}
+	if probeURL(strings.TrimSuffix(url, "/")+"/index.yaml") == nil {
+		return nil
+	}
+	return err
+}
+
+func probeURL(url string) error {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Head(url)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusMethodNotAllowed {
+		return fmt.Errorf("cannot reach %s: %s", url, resp.Status)
+	}
+	return nil
+}
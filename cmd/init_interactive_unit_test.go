@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAskInitPlan(t *testing.T) {
+	chartDir := t.TempDir()
+	answers := []string{
+		"helm",             // unknown type, asked again
+		"helm-promise",     // type
+		"Invalid_Name",     // invalid name, asked again
+		"postgresql",       // name
+		"syntasso",         // group without a dot, asked again
+		"syntasso.io",      // group
+		"Database",         // kind
+		"",                 // version
+		"",                 // dir
+		"./does-not-exist", // chart url that cannot be read, asked again
+		chartDir,           // chart url
+		"",                 // chart name
+		"1.2.3",            // chart version
+		"y",                // split
+	}
+	promptReader = bufio.NewReader(strings.NewReader(strings.Join(answers, "\n") + "\n"))
+	defer func() { promptReader = bufio.NewReader(os.Stdin) }()
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	plan, err := askInitPlan()
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("askInitPlan() error = %v", err)
+	}
+
+	want := "kratix init helm-promise postgresql --group 'syntasso.io' --kind 'Database' --chart-url '" + chartDir + "' --chart-version '1.2.3' --split"
+	if got := plan.commandLine(); got != want {
+		t.Errorf("commandLine() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateReachable(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "chart.tgz")
+	if err := os.WriteFile(existing, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for source, wantErr := range map[string]bool{
+		existing:                                 false,
+		filepath.Join(dir, "missing.tgz"):        true,
+		"oci://registry.io/charts/redis":         false,
+		"git::https://github.com/org/module.git": false,
+	} {
+		if err := validateReachable(source); (err != nil) != wantErr {
+			t.Errorf("validateReachable(%q) error = %v, wantErr %t", source, err, wantErr)
+		}
+	}
+}
+
+func TestProbeChartURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/charts/index.yaml" && r.URL.Path != "/redis.tgz" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for url, wantErr := range map[string]bool{
+		server.URL + "/redis.tgz": false,
+		server.URL + "/charts":    false,
+		server.URL + "/charts/":   false,
+		server.URL + "/missing":   true,
+	} {
+		if err := probeChartURL(url); (err != nil) != wantErr {
+			t.Errorf("probeChartURL(%q) error = %v, wantErr %t", url, err, wantErr)
+		}
+	}
+}
+
+func TestValidateReachableWarnsForUnreachableURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+
+	for _, source := range []string{
+		server.URL + "/module.zip",
+		server.URL + "/module.zip//modules/vpc",
+		server.URL + "/module.git?ref=v1.0.0",
+	} {
+		if err := validateReachable(source); err != nil {
+			t.Errorf("validateReachable(%q) error = %v, want nil", source, err)
+		}
+		if err := validateChartURL(source); err != nil {
+			t.Errorf("validateChartURL(%q) error = %v, want nil", source, err)
+		}
+	}
+}
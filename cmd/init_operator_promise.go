@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -51,7 +50,10 @@ func InitPromiseFromOperator(cmd *cobra.Command, args []string) error {
 	promiseName := args[0]
 
 	if plural == "" {
-		plural = fmt.Sprintf("%ss", strings.ToLower(kind))
+		var err error
+		if plural, err = pluralize(kind); err != nil {
+			return err
+		}
 	}
 
 	dependencies, err := buildDependencies(operatorManifestsDir)
@@ -197,19 +199,14 @@ func updateOperatorCrd(crd *apiextensionsv1.CustomResourceDefinition, storedVers
 }
 
 func writePromiseFiles(outputDir string, filesToWrite map[string]any) error {
-	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-			return err
-		}
+	if err := promiseFiles.mkdirAll(outputDir); err != nil {
+		return err
 	}
 
 	for key, value := range filesToWrite {
 		switch v := value.(type) {
 		case map[string]any:
 			subdir := filepath.Join(outputDir, key)
-			if err := os.MkdirAll(subdir, os.ModePerm); err != nil {
-				return err
-			}
 			if err := writePromiseFiles(subdir, v); err != nil {
 				return err
 			}
@@ -218,7 +215,7 @@ func writePromiseFiles(outputDir string, filesToWrite map[string]any) error {
 			if err != nil {
 				return err
 			}
-			if err = os.WriteFile(filepath.Join(outputDir, key), fileContentBytes, filePerm); err != nil {
+			if err = promiseFiles.writeFile(filepath.Join(outputDir, key), fileContentBytes); err != nil {
 				return err
 			}
 		}
@@ -18,10 +18,13 @@ var initPromiseCmd = &cobra.Command{
 	Short: "Initialize a new Promise",
 	Long:  `Initialize a new Promise within the current directory, with all the necessary files to get started`,
 	Example: `  # initialize a new promise with the api group and provided kind
-  kratix init promise postgresql --group syntasso.io --kind database
+  kratix init promise postgresql --group syntasso.io --kind Database
 
   # initialize a new promise with the specified version
-  kratix init promise postgresql --group syntasso.io --kind database --version v1
+  kratix init promise postgresql --group syntasso.io --kind Database --version v1
+
+  # initialize a new promise from your organisation's Promise template
+  kratix init promise postgresql --group syntasso.io --kind Database --template git::https://github.com/myorg/promise-template.git?ref=v1 --set team=data
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: InitPromise,
@@ -35,8 +38,16 @@ const (
 	resourceConfigureWorkflowFileName = "workflows/resource/configure/workflow.yaml"
 )
 
+var (
+	promiseTemplate          string
+	promiseTemplateSetValues []string
+)
+
 func init() {
 	initCmd.AddCommand(initPromiseCmd)
+	initPromiseCmd.Flags().StringVar(&promiseTemplate, "template", "", "Directory or git URL of a custom Promise template. Its files are rendered on top of the generated Promise, "+
+		"and extra values can be declared in its "+promiseTemplateManifestFileName)
+	initPromiseCmd.Flags().StringArrayVar(&promiseTemplateSetValues, "set", nil, "Value for the custom Promise template, in KEY=VALUE format. Can be repeated")
 }
 
 type promiseTemplateValues struct {
@@ -51,17 +62,31 @@ type promiseTemplateValues struct {
 	PromiseConfigure     string
 	CRDSchema            string
 	DestinationSelectors string
+	Dependencies         string
 	ExtraFlags           string
+	// Values holds the extra values declared by a custom Promise template
+	Values map[string]string
 }
 
 func InitPromise(cmd *cobra.Command, args []string) error {
 	promiseName := args[0]
 
-	templateValues, err := generateTemplateValues(promiseName, "promise", "", "[]", "[]", "")
+	templateValues, err := generateTemplateValues(promiseName, "promise", promiseTemplateExtraFlags(), "[]", "[]", "")
 	if err != nil {
 		return err
 	}
 
+	var customTemplateDir string
+	if promiseTemplate != "" {
+		templateDir, values, cleanup, err := preparePromiseTemplate(promiseTemplate, promiseTemplateSetValues)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		customTemplateDir = templateDir
+		templateValues.Values = values
+	}
+
 	templates := map[string]string{
 		resourceFileName: fmt.Sprintf("templates/promise/%s.tpl", resourceFileName),
 		"README.md":      "templates/promise/README.md.tpl",
@@ -78,6 +103,12 @@ func InitPromise(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if customTemplateDir != "" {
+		if err := renderPromiseTemplate(customTemplateDir, outputDir, templateValues); err != nil {
+			return err
+		}
+	}
+
 	dirName := "current"
 	if outputDir != "." {
 		dirName = outputDir
@@ -87,13 +118,27 @@ func InitPromise(cmd *cobra.Command, args []string) error {
 
 }
 
+func promiseTemplateExtraFlags() string {
+	var flags []string
+	if promiseTemplate != "" {
+		flags = append(flags, "--template", shellQuoteArg(promiseTemplate))
+	}
+	for _, setValue := range promiseTemplateSetValues {
+		flags = append(flags, "--set", shellQuoteArg(setValue))
+	}
+	return strings.Join(flags, " ")
+}
+
 func generateTemplateValues(promiseName, subCommand, extraFlags, resourceConfigure, promiseConfigure, crdSchema string) (promiseTemplateValues, error) {
 	if version == "" {
 		version = "v1alpha1"
 	}
 
 	if plural == "" {
-		plural = fmt.Sprintf("%ss", strings.ToLower(kind))
+		var err error
+		if plural, err = pluralize(kind); err != nil {
+			return promiseTemplateValues{}, err
+		}
 	}
 
 	if crdSchema == "" {
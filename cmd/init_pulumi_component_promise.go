@@ -67,6 +67,7 @@ func init() {
 	pulumiComponentPromiseCmd.Flags().StringVar(&pulumiComponent, "component", "", "Pulumi component token to use from the schema")
 	pulumiComponentPromiseCmd.Flags().StringVar(&pulumiSchemaBearerTokenSecret, "schema-bearer-token-secret", "", "Secret reference in SECRET_NAME:KEY format to set PULUMI_ACCESS_TOKEN for private schema fetches")
 	pulumiComponentPromiseCmd.Flags().StringVar(&pulumiStackAccessTokenSecret, "stack-access-token-secret", "", "Secret reference in SECRET_NAME:KEY format to set Stack spec.envRefs.PULUMI_ACCESS_TOKEN for Pulumi Cloud access")
+	pulumiComponentPromiseCmd.Flags().BoolVar(&withDelete, "with-delete", false, withDeleteFlagUsage)
 
 	pulumiComponentPromiseCmd.MarkFlagRequired("schema")
 }
@@ -119,7 +120,10 @@ func initPulumiComponentPromiseFromSelection(promiseName string, component pulum
 		version = "v1alpha1"
 	}
 	if plural == "" {
-		plural = fmt.Sprintf("%ss", strings.ToLower(kind))
+		var err error
+		if plural, err = pluralize(kind); err != nil {
+			return err
+		}
 	}
 
 	crd, err := buildPulumiCRD(specSchema)
@@ -214,6 +218,12 @@ func initPulumiComponentPromiseFromSelection(promiseName string, component pulum
 		return err
 	}
 
+	if withDelete {
+		if err := writeResourceDeleteWorkflow(promiseName, "pulumi"); err != nil {
+			return err
+		}
+	}
+
 	fmt.Println("Pulumi component Promise generated successfully.")
 	return nil
 }
@@ -245,6 +255,9 @@ func buildPulumiPromiseExtraFlags() string {
 	if split {
 		flags = append(flags, "--split")
 	}
+	if withDelete {
+		flags = append(flags, "--with-delete")
+	}
 
 	return strings.Join(flags, " ")
 }
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -86,6 +85,7 @@ func init() {
 		"defaults to versions.tf and providers.tf",
 	)
 	terraformModuleCmd.Flags().BoolVarP(&generateOutputs, "generate-outputs", "", false, "(Optional) generate Terraform 'output' blocks for the module")
+	terraformModuleCmd.Flags().BoolVar(&withDelete, "with-delete", false, withDeleteFlagUsage)
 	terraformModuleCmd.MarkFlagRequired("module-source")
 }
 
@@ -151,6 +151,9 @@ func InitFromTerraformModule(cmd *cobra.Command, args []string) error {
 	if generateOutputs {
 		extraFlags = fmt.Sprintf("%s --generate-outputs", extraFlags)
 	}
+	if withDelete {
+		extraFlags = fmt.Sprintf("%s --with-delete", extraFlags)
+	}
 	templateValues, err := generateTemplateValues(promiseName, "tf-module-promise", extraFlags, resourceConfigure, promiseConfigure, string(crdSchema))
 	if err != nil {
 		return fmt.Errorf("failed to generate template values: %w", err)
@@ -180,6 +183,12 @@ func InitFromTerraformModule(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to write promise dependencies: %w", err)
 	}
 
+	if withDelete {
+		if err := writeResourceDeleteWorkflow(promiseName, "terraform"); err != nil {
+			return fmt.Errorf("failed to write resource delete workflow: %w", err)
+		}
+	}
+
 	fmt.Println("Promise generated successfully. It is set to schedule to Destinations with the label `environment: terraform` by default. To modify this behavior, update the `.spec.destinationSelectors` field in `promise.yaml`")
 	return nil
 }
@@ -279,28 +288,20 @@ func writeDependencyFiles(versionProviderFilepaths []string) error {
 	containerDir := filepath.Join(dir, "workflows", pipelineCmdArgs.Lifecycle, pipelineCmdArgs.Action, pipelineCmdArgs.Pipeline, containerName)
 	resourcesDir := filepath.Join(containerDir, "resources")
 	for _, providerFilepath := range versionProviderFilepaths {
-		sourceFile, err := os.Open(providerFilepath)
+		providerFile, err := os.ReadFile(providerFilepath)
 		if err != nil {
 			return fmt.Errorf("error opening provider file: %s", err)
 		}
-		defer sourceFile.Close()
 
 		baseFileName := filepath.Base(providerFilepath)
-		destFile, err := os.Create(filepath.Join(outputDir, resourcesDir, baseFileName))
-		if err != nil {
-			return fmt.Errorf("error creating provider file: %s", err)
-		}
-		defer destFile.Close()
-
-		_, err = io.Copy(destFile, sourceFile)
-		if err != nil {
+		if err := promiseFiles.writeFile(filepath.Join(outputDir, resourcesDir, baseFileName), providerFile); err != nil {
 			return fmt.Errorf("error copying provider file: %s", err)
 		}
 	}
 
 	scriptsDir := filepath.Join(containerDir, "scripts")
 	pipelineScriptContent := "#!/usr/bin/env sh\n\ncp /resources/* /kratix/output"
-	if err := os.WriteFile(filepath.Join(outputDir, scriptsDir, "pipeline.sh"), []byte(pipelineScriptContent), filePerm); err != nil {
+	if err := promiseFiles.writeFile(filepath.Join(outputDir, scriptsDir, "pipeline.sh"), []byte(pipelineScriptContent)); err != nil {
 		return err
 	}
 
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syntasso/kratix-cli/internal"
+	"sigs.k8s.io/yaml"
+)
+
+const promiseTemplateManifestFileName = "kratix-template.yaml"
+
+// promiseTemplateManifest declares the extra values a custom Promise template
+// expects. It lives at the root of the template and is not rendered.
+type promiseTemplateManifest struct {
+	Values []promiseTemplateValue `json:"values,omitempty"`
+}
+
+type promiseTemplateValue struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// preparePromiseTemplate fetches a custom Promise template and resolves the
+// values declared in its manifest, so that problems are reported before any
+// file is written.
+func preparePromiseTemplate(source string, setValues []string) (string, map[string]string, func(), error) {
+	templateDir, cleanup, err := internal.FetchTemplateSource(source)
+	if err != nil {
+		return "", nil, cleanup, err
+	}
+
+	manifest, err := loadPromiseTemplateManifest(templateDir)
+	if err != nil {
+		cleanup()
+		return "", nil, func() {}, err
+	}
+
+	values, err := resolvePromiseTemplateValues(manifest, setValues, stdinIsTerminal())
+	if err != nil {
+		cleanup()
+		return "", nil, func() {}, err
+	}
+	return templateDir, values, cleanup, nil
+}
+
+// renderPromiseTemplate renders a custom Promise template on top of the
+// files already generated in outputDir. Files in the template replace
+// generated files with the same path.
+func renderPromiseTemplate(templateDir, outputDir string, templateValues promiseTemplateValues) error {
+	return templateDirectory(templateDir, outputDir, func(relPath string) string {
+		if relPath == promiseTemplateManifestFileName {
+			return ""
+		}
+		return relPath
+	}, templateValues)
+}
+
+func loadPromiseTemplateManifest(templateDir string) (*promiseTemplateManifest, error) {
+	manifest := &promiseTemplateManifest{}
+	manifestPath := filepath.Join(templateDir, promiseTemplateManifestFileName)
+	manifestBytes, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", promiseTemplateManifestFileName, err)
+	}
+	for _, value := range manifest.Values {
+		if value.Name == "" {
+			return nil, fmt.Errorf("invalid %s: every value must have a name", promiseTemplateManifestFileName)
+		}
+	}
+	return manifest, nil
+}
+
+// resolvePromiseTemplateValues returns the values for the template, taken
+// from --set, then from an interactive prompt, then from the manifest default.
+func resolvePromiseTemplateValues(manifest *promiseTemplateManifest, setValues []string, interactive bool) (map[string]string, error) {
+	values := map[string]string{}
+	for _, setValue := range setValues {
+		key, value, ok := strings.Cut(setValue, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value %q, expected KEY=VALUE", setValue)
+		}
+		values[key] = value
+	}
+
+	var missing []string
+	for _, declared := range manifest.Values {
+		if _, ok := values[declared.Name]; ok {
+			continue
+		}
+
+		value := declared.Default
+		if interactive {
+			label := declared.Name
+			if declared.Description != "" {
+				label = fmt.Sprintf("%s (%s)", declared.Name, declared.Description)
+			}
+			var err error
+			if value, err = prompt(label, declared.Default); err != nil {
+				return nil, err
+			}
+		}
+
+		if value == "" && declared.Required {
+			missing = append(missing, declared.Name)
+			continue
+		}
+		values[declared.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required template values: %s; set them with --set KEY=VALUE", strings.Join(missing, ", "))
+	}
+	return values, nil
+}
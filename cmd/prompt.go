@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var promptReader = bufio.NewReader(os.Stdin)
+
+// stdinIsTerminal returns true when the CLI is attached to an interactive
+// terminal and can prompt the user for input.
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// prompt asks the user for a value, returning defaultValue when the answer is
+// empty.
+func prompt(label, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Printf("%s [%s]: ", label, defaultValue)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	answer, err := promptReader.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// confirm asks the user a yes/no question. When assumeYes is set the question
+// is skipped; when there is no terminal to ask on, confirmation is refused
+// and the user is told to pass --yes.
+func confirm(question string, assumeYes bool) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("cannot ask for confirmation without a terminal; pass --yes to continue")
+	}
+
+	answer, err := prompt(question+" [y/N]", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate [-- INIT-FLAGS...]",
+	Short: "Regenerate a Promise with the init command it was generated with",
+	Long: `Regenerate a Promise by running the init command recorded in .kratix/generator.yaml again, e.g. to pick
+up a new chart version, module ref or XRD revision. Flags given after -- replace the recorded ones.
+
+The result is merged with the Promise in --dir: changes made to the generated files since they were
+generated are kept, and lines changed both locally and by the generator are marked as conflicts.`,
+	Example: `  # regenerate the Promise in the current directory
+  kratix regenerate
+
+  # regenerate a Promise from a newer chart version
+  kratix regenerate -- --chart-version 1.2.0`,
+	RunE: RegeneratePromise,
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateCmd)
+	regenerateCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory of the Promise to regenerate. Default to current working directory.")
+}
+
+func RegeneratePromise(cmd *cobra.Command, args []string) error {
+	config, err := loadGeneratorConfig(dir)
+	if err != nil {
+		return err
+	}
+
+	generatorCmd, _, err := rootCmd.Find(strings.Fields(config.Command))
+	if err != nil || generatorCmd.Parent() != initCmd {
+		return fmt.Errorf("unknown generator command %q in %s", config.Command, generatorFileName)
+	}
+	if err := generatorCmd.ParseFlags(generatorArgs(generatorCmd, config, args)); err != nil {
+		return err
+	}
+	outputDir = dir
+	if generatorCmd.Args != nil {
+		if err := generatorCmd.Args(generatorCmd, config.Args); err != nil {
+			return err
+		}
+	}
+	if err := validateInitFlags(generatorCmd, config.Args); err != nil {
+		return err
+	}
+	if err := generatorCmd.ValidateRequiredFlags(); err != nil {
+		return err
+	}
+
+	// the generator writes to memory only; its files are merged below
+	writer := promiseFiles
+	promiseFiles = &fileWriter{dryRun: true, out: io.Discard, pending: map[string][]byte{}}
+	promiseFiles.record()
+	err = generatorCmd.RunE(generatorCmd, config.Args)
+	generated := promiseFiles.stopRecording()
+	promiseFiles = writer
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := mergeGeneratedFiles(dir, generated)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerator(dir, newGeneratorConfig(generatorCmd, config.Args), generated); err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("the regenerated Promise conflicts with local changes in %s; resolve the conflicts marked with <<<<<<< and >>>>>>>", strings.Join(conflicts, ", "))
+	}
+	fmt.Printf("Promise regenerated with `kratix %s`\n", config.Command)
+	return nil
+}
+
+// generatorArgs returns the recorded flags of the generator, followed by
+// the flags given to regenerate. Recorded flags that are given again are
+// dropped, so that the new values replace them.
+func generatorArgs(generatorCmd *cobra.Command, config generatorConfig, overrides []string) []string {
+	// merges the flags inherited from init into Flags()
+	generatorCmd.InheritedFlags()
+	flags := generatorCmd.Flags()
+	for _, arg := range overrides {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			delete(config.Flags, name)
+		case strings.HasPrefix(arg, "-") && len(name) == 1:
+			if f := flags.ShorthandLookup(name); f != nil {
+				delete(config.Flags, f.Name)
+			}
+		}
+	}
+	return append(config.commandLine(), overrides...)
+}
+
+// mergeGeneratedFiles merges the newly generated files into the Promise in
+// promiseDir, and returns the files with conflicts.
+func mergeGeneratedFiles(promiseDir string, generated map[string][]byte) ([]string, error) {
+	generatedDir := filepath.Join(promiseDir, generatorDirName, generatedFilesDirName)
+
+	var paths []string
+	for path := range generated {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var conflicts []string
+	regenerated := map[string]bool{}
+	for _, path := range paths {
+		relPath, err := filepath.Rel(promiseDir, path)
+		if err != nil || strings.HasPrefix(relPath, "..") || strings.HasPrefix(relPath, generatorDirName) {
+			continue
+		}
+		regenerated[relPath] = true
+
+		base, err := readFileIfExists(filepath.Join(generatedDir, relPath))
+		if err != nil {
+			return nil, err
+		}
+		current, err := readFileIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil && base != nil {
+			// deleted since it was generated
+			continue
+		}
+
+		merged, conflict := mergeGeneratedFile(relPath, base, current, generated[path])
+		if conflict {
+			conflicts = append(conflicts, relPath)
+		}
+		if current != nil && bytes.Equal(merged, current) {
+			continue
+		}
+		if err := promiseFiles.writeFile(path, merged); err != nil {
+			return nil, err
+		}
+	}
+
+	err := filepath.WalkDir(generatedDir, func(basePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(generatedDir, basePath)
+		if err != nil || regenerated[relPath] {
+			return err
+		}
+		base, err := os.ReadFile(basePath)
+		if err != nil {
+			return err
+		}
+		current, err := readFileIfExists(filepath.Join(promiseDir, relPath))
+		if err != nil || current == nil {
+			return err
+		}
+		if !bytes.Equal(base, current) {
+			fmt.Printf("%s is no longer generated, but was kept as it has local changes\n", relPath)
+			return nil
+		}
+		return promiseFiles.removeAll(filepath.Join(promiseDir, relPath))
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+func readFileIfExists(path string) ([]byte, error) {
+	contents, err := promiseFiles.readFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return contents, err
+}
+
+// mergeGeneratedFile merges a generated file. YAML files are also merged
+// once formatted the same way, as commands such as `kratix add container`
+// rewrite the whole file; their comments are lost when they are.
+func mergeGeneratedFile(relPath string, base, current, generated []byte) ([]byte, bool) {
+	merged, conflict := mergeFileContents(base, current, generated)
+	if !conflict || (filepath.Ext(relPath) != ".yaml" && filepath.Ext(relPath) != ".yml") {
+		return merged, conflict
+	}
+
+	var formatted [3][]byte
+	for i, contents := range [][]byte{base, current, generated} {
+		if contents == nil {
+			continue
+		}
+		jsonBytes, err := yaml.YAMLToJSON(contents)
+		if err != nil {
+			return merged, conflict
+		}
+		if formatted[i], err = yaml.JSONToYAML(jsonBytes); err != nil {
+			return merged, conflict
+		}
+	}
+	if formattedMerge, formattedConflict := mergeFileContents(formatted[0], formatted[1], formatted[2]); !formattedConflict {
+		return formattedMerge, false
+	}
+	return merged, conflict
+}
+
+// mergeFileContents merges the changes made to base in current and in
+// generated, line by line. Lines changed differently on both sides are
+// marked as conflicts.
+func mergeFileContents(base, current, generated []byte) ([]byte, bool) {
+	switch {
+	case bytes.Equal(base, current) || bytes.Equal(current, generated):
+		return generated, false
+	case bytes.Equal(base, generated):
+		return current, false
+	}
+
+	baseLines := splitLines(base)
+	currentLines, generatedLines := splitLines(current), splitLines(generated)
+	changes := append(lineChanges(baseLines, currentLines, false), lineChanges(baseLines, generatedLines, true)...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].start < changes[j].start })
+
+	var merged []string
+	conflict := false
+	next := 0
+	for i := 0; i < len(changes); {
+		start, end := changes[i].start, changes[i].end
+		j := i + 1
+		for ; j < len(changes) && changes[j].start <= end; j++ {
+			end = max(end, changes[j].end)
+		}
+		merged = append(merged, baseLines[next:start]...)
+
+		var currentChanges, generatedChanges []lineChange
+		for _, change := range changes[i:j] {
+			if change.generated {
+				generatedChanges = append(generatedChanges, change)
+			} else {
+				currentChanges = append(currentChanges, change)
+			}
+		}
+		currentRange := applyLineChanges(baseLines, start, end, currentChanges)
+		generatedRange := applyLineChanges(baseLines, start, end, generatedChanges)
+		switch {
+		case len(generatedChanges) == 0:
+			merged = append(merged, currentRange...)
+		case len(currentChanges) == 0 || strings.Join(currentRange, "") == strings.Join(generatedRange, ""):
+			merged = append(merged, generatedRange...)
+		default:
+			conflict = true
+			merged = append(merged, "<<<<<<< local\n")
+			merged = append(merged, withTrailingNewline(currentRange)...)
+			merged = append(merged, "=======\n")
+			merged = append(merged, withTrailingNewline(generatedRange)...)
+			merged = append(merged, ">>>>>>> regenerated\n")
+		}
+		next, i = end, j
+	}
+	merged = append(merged, baseLines[next:]...)
+	return []byte(strings.Join(merged, "")), conflict
+}
+
+// lineChange replaces base[start:end] with lines
+type lineChange struct {
+	start, end int
+	lines      []string
+	generated  bool
+}
+
+func lineChanges(base, changed []string, generated bool) []lineChange {
+	var changes []lineChange
+	for _, op := range difflib.NewMatcher(base, changed).GetOpCodes() {
+		if op.Tag == 'e' {
+			continue
+		}
+		changes = append(changes, lineChange{start: op.I1, end: op.I2, lines: changed[op.J1:op.J2], generated: generated})
+	}
+	return changes
+}
+
+// applyLineChanges returns base[start:end] with the changes applied
+func applyLineChanges(base []string, start, end int, changes []lineChange) []string {
+	var lines []string
+	next := start
+	for _, change := range changes {
+		lines = append(lines, base[next:change.start]...)
+		lines = append(lines, change.lines...)
+		next = change.end
+	}
+	return append(lines, base[next:end]...)
+}
+
+func splitLines(contents []byte) []string {
+	lines := strings.SplitAfter(string(contents), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func withTrailingNewline(lines []string) []string {
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines = append(lines[:len(lines)-1:len(lines)-1], lines[len(lines)-1]+"\n")
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import "testing"
+
+func TestMergeFileContents(t *testing.T) {
+	base := "name: postgresql\nversion: 1.0.0\nimage: postgres:15\nreplicas: 1\n"
+	tests := []struct {
+		name               string
+		current, generated string
+		want               string
+		wantConflict       bool
+	}{
+		{
+			name:      "unchanged locally",
+			current:   base,
+			generated: "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 1\n",
+			want:      "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 1\n",
+		},
+		{
+			name:      "unchanged by the generator",
+			current:   "name: postgresql\nversion: 1.0.0\nimage: postgres:15\nreplicas: 3\n",
+			generated: base,
+			want:      "name: postgresql\nversion: 1.0.0\nimage: postgres:15\nreplicas: 3\n",
+		},
+		{
+			name:      "different lines changed on both sides",
+			current:   "name: postgresql\nversion: 1.0.0\nimage: postgres:15\nreplicas: 3\n# my notes\n",
+			generated: "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 1\n",
+			want:      "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 3\n# my notes\n",
+		},
+		{
+			name:      "same change on both sides",
+			current:   "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 3\n",
+			generated: "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 1\n",
+			want:      "name: postgresql\nversion: 2.0.0\nimage: postgres:15\nreplicas: 3\n",
+		},
+		{
+			name:         "same line changed differently",
+			current:      "name: postgresql\nversion: 1.0.0\nimage: postgres:16\nreplicas: 1\n",
+			generated:    "name: postgresql\nversion: 1.0.0\nimage: postgres:17\nreplicas: 1\n",
+			want:         "name: postgresql\nversion: 1.0.0\n<<<<<<< local\nimage: postgres:16\n=======\nimage: postgres:17\n>>>>>>> regenerated\nreplicas: 1\n",
+			wantConflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflict := mergeFileContents([]byte(base), []byte(tt.current), []byte(tt.generated))
+			if string(got) != tt.want {
+				t.Errorf("mergeFileContents() = %q, want %q", got, tt.want)
+			}
+			if conflict != tt.wantConflict {
+				t.Errorf("mergeFileContents() conflict = %v, want %v", conflict, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestMergeFileContentsWithoutBase(t *testing.T) {
+	got, conflict := mergeFileContents(nil, []byte("a\n"), []byte("b\n"))
+	if !conflict || string(got) != "<<<<<<< local\na\n=======\nb\n>>>>>>> regenerated\n" {
+		t.Errorf("mergeFileContents() = %q, %v, want a conflict", got, conflict)
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Command to remove from Kratix resources",
+	Long:  "Command to remove from Kratix resources",
+}
+
+var deleteFiles, assumeYes bool
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+}
+
+func addRemoveFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
+	cmd.Flags().BoolVar(&deleteFiles, "delete-files", false, "Also delete the matching directory under workflows/.")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not ask for confirmation.")
+}
+
+// applyRemoval writes the updated workflow file and deletes dirsToDelete when
+// --delete-files is set, after asking the user to confirm. With --dry-run or
+// --diff it only prints what would change.
+func applyRemoval(w *workflowFile, description string, dirsToDelete []string) error {
+	if !deleteFiles {
+		dirsToDelete = nil
+	}
+
+	if promiseFiles.skipWrites() {
+		fmt.Printf("Would remove %s from %s\n", description, w.path)
+		if err := w.write(); err != nil {
+			return err
+		}
+		for _, d := range dirsToDelete {
+			if err := promiseFiles.removeAll(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	question := fmt.Sprintf("Remove %s from %s?", description, w.path)
+	if len(dirsToDelete) > 0 {
+		question = fmt.Sprintf("Remove %s from %s and delete %s?", description, w.path, strings.Join(dirsToDelete, ", "))
+	}
+	ok, err := confirm(question, assumeYes)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	if err := w.write(); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s from %s\n", description, w.path)
+
+	for _, d := range dirsToDelete {
+		if err := promiseFiles.removeAll(d); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", d)
+	}
+	return nil
+}
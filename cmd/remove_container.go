@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+)
+
+var removeContainerCmd = &cobra.Command{
+	Use:   "container LIFECYCLE/ACTION/PIPELINE-NAME --name CONTAINER-NAME",
+	Short: "Removes a container from the named workflow",
+	Example: `  # remove the container 'deploy-deps' from the promise configure pipeline 'pipeline0'
+  kratix remove container promise/configure/pipeline0 --name deploy-deps
+
+  # also delete workflows/resource/configure/instance/create-db without asking for confirmation
+  kratix remove container resource/configure/instance --name create-db --delete-files --yes
+
+  # show what would be removed
+  kratix remove container resource/configure/instance --name create-db --dry-run`,
+	RunE: RemoveContainer,
+	Args: cobra.ExactArgs(1),
+}
+
+var removeContainerName string
+
+func init() {
+	removeCmd.AddCommand(removeContainerCmd)
+	removeContainerCmd.Flags().StringVarP(&removeContainerName, "name", "n", "", "The name of the container to remove.")
+	addRemoveFlags(removeContainerCmd)
+	removeContainerCmd.MarkFlagRequired("name")
+}
+
+func RemoveContainer(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
+	}
+
+	w, err := loadWorkflowFile(dir, c)
+	if err != nil {
+		return err
+	}
+
+	pipelineIdx, containerIdx, err := w.containerIdx(c.Pipeline, removeContainerName)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("container %s from pipeline %s/%s/%s", removeContainerName, c.Lifecycle, c.Action, c.Pipeline)
+	dirToDelete := workflowDir(dir, c.Lifecycle, c.Action, c.Pipeline, removeContainerName)
+
+	pipeline := &w.pipelines[pipelineIdx]
+	pipeline.Spec.Containers = slices.Delete(pipeline.Spec.Containers, containerIdx, containerIdx+1)
+	if len(pipeline.Spec.Containers) == 0 {
+		// a pipeline must have at least one container
+		w.pipelines = slices.Delete(w.pipelines, pipelineIdx, pipelineIdx+1)
+		description += fmt.Sprintf(", and the now empty pipeline %s", c.Pipeline)
+		dirToDelete = workflowDir(dir, c.Lifecycle, c.Action, c.Pipeline, "")
+	}
+
+	return applyRemoval(w, description, []string{dirToDelete})
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+)
+
+var removePipelineCmd = &cobra.Command{
+	Use:   "pipeline LIFECYCLE/ACTION/PIPELINE-NAME",
+	Short: "Removes a pipeline and all its containers from the named workflow",
+	Example: `  # remove the resource configure pipeline 'instance'
+  kratix remove pipeline resource/configure/instance
+
+  # also delete workflows/resource/configure/instance without asking for confirmation
+  kratix remove pipeline resource/configure/instance --delete-files --yes`,
+	RunE: RemovePipeline,
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	removeCmd.AddCommand(removePipelineCmd)
+	addRemoveFlags(removePipelineCmd)
+}
+
+func RemovePipeline(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
+	}
+
+	w, err := loadWorkflowFile(dir, c)
+	if err != nil {
+		return err
+	}
+
+	pipelineIdx, err := w.pipelineIdx(c.Pipeline)
+	if err != nil {
+		return err
+	}
+	w.pipelines = slices.Delete(w.pipelines, pipelineIdx, pipelineIdx+1)
+
+	description := fmt.Sprintf("pipeline %s/%s/%s", c.Lifecycle, c.Action, c.Pipeline)
+	return applyRemoval(w, description, []string{workflowDir(dir, c.Lifecycle, c.Action, c.Pipeline, "")})
+}
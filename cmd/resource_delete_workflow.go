@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+)
+
+var withDelete bool
+
+const withDeleteFlagUsage = "(Optional) generate a resource delete workflow with a cleanup script for the backend"
+
+// writeResourceDeleteWorkflow adds a resource delete pipeline to the Promise
+// in outputDir, with a container whose script describes how resources of the
+// given backend (terraform, helm or pulumi) are cleaned up.
+func writeResourceDeleteWorkflow(promiseName, backend string) error {
+	c := &pipelineutils.PipelineCmdArgs{
+		Lifecycle: "resource",
+		Action:    "delete",
+		Pipeline:  "instance-delete",
+	}
+	containerName := fmt.Sprintf("%s-resource-delete", backend)
+	containerImage := fmt.Sprintf("my-registry.io/my-org/kratix/%s:v0.0.1", containerName)
+
+	if err := generateWorkflow(c, containerName, containerImage, outputDir, true); err != nil {
+		return fmt.Errorf("error generating workflows for %s/%s/%s: %s", c.Lifecycle, c.Action, c.Pipeline, err)
+	}
+
+	scriptPath := filepath.Join("workflows", c.Lifecycle, c.Action, c.Pipeline, containerName, "scripts", "pipeline.sh")
+	templates := map[string]string{
+		scriptPath: fmt.Sprintf("templates/workflows/delete/%s.sh.tpl", backend),
+	}
+	return templateFiles(workflowTemplates, outputDir, templates, containerTemplateValues{
+		PromiseName:   promiseName,
+		Lifecycle:     c.Lifecycle,
+		Action:        c.Action,
+		Pipeline:      c.Pipeline,
+		ContainerName: containerName,
+		Image:         containerImage,
+		Language:      "bash",
+	})
+}
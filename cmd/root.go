@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"bytes"
-	"embed"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -46,7 +46,7 @@ func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
-func templateFiles(templates embed.FS, outputDir string, filesToTemplate map[string]string, templateValues any) error {
+func templateFiles(templates fs.FS, outputDir string, filesToTemplate map[string]string, templateValues any) error {
 	for path, tmpl := range filesToTemplate {
 		t, err := template.New(filepath.Base(tmpl)).Funcs(sprig.FuncMap()).ParseFS(templates, tmpl)
 		if err != nil {
@@ -57,19 +57,56 @@ func templateFiles(templates embed.FS, outputDir string, filesToTemplate map[str
 		if err := t.Execute(data, templateValues); err != nil {
 			return err
 		}
-		fullPath := filepath.Join(outputDir, path)
-		parentDir := filepath.Dir(fullPath)
-		if _, err := os.Stat(parentDir); os.IsNotExist(err) {
-			if err := os.MkdirAll(parentDir, os.ModePerm); err != nil {
-				return err
+		if err := promiseFiles.writeFile(filepath.Join(outputDir, path), data.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateDirectory renders every file in templateDir into outputDir. Files
+// ending in .tpl are rendered with templateValues and have the suffix
+// removed; other files are copied as-is. outputPath maps each file's path
+// (relative to templateDir, without .tpl) to its path relative to outputDir;
+// returning an empty string skips the file.
+func templateDirectory(templateDir, outputDir string, outputPath func(relPath string) string, templateValues any) error {
+	templates := map[string]string{}
+	err := filepath.WalkDir(templateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
 			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := outputPath(strings.TrimSuffix(relPath, ".tpl"))
+		if destPath == "" {
+			return nil
+		}
+
+		if strings.HasSuffix(relPath, ".tpl") {
+			templates[destPath] = filepath.ToSlash(relPath)
+			return nil
 		}
 
-		if err := os.WriteFile(fullPath, data.Bytes(), filePerm); err != nil {
+		contents, err := os.ReadFile(path)
+		if err != nil {
 			return err
 		}
+		return promiseFiles.writeFile(filepath.Join(outputDir, destPath), contents)
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+
+	return templateFiles(os.DirFS(templateDir), outputDir, templates, templateValues)
 }
 
 func handlePotentialPluginCommand(args []string) error {
@@ -30,6 +30,10 @@ spec:
 {{- if .DestinationSelectors }}
   destinationSelectors:
 {{ .DestinationSelectors | indent 4 }}
+{{- end }}
+{{- if .Dependencies }}
+  dependencies:
+{{ .Dependencies | indent 4 }}
 {{- end }}
   workflows:
     promise:
@@ -0,0 +1,13 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+# documents written by the configure pipelines, so nothing needs to be written
+# to /kratix/output. Clean up anything created outside of Kratix here, such as
+# cloud resources, DNS records or entries in other systems.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+namespace=$(yq '.metadata.namespace' /kratix/input/object.yaml)
+
+echo "Cleaning up ${name} ${namespace}"
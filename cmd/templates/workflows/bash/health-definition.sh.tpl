@@ -0,0 +1,23 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# Writes the HealthDefinition of the resource, from the template in
+# /resources/health-definition.yaml. Kratix schedules it with the rest of the
+# output, and the health check runs on the Destination it is scheduled to.
+
+export NAME="$(yq '.metadata.name' /kratix/input/object.yaml)"
+export NAMESPACE="$(yq '.metadata.namespace' /kratix/input/object.yaml)"
+export INPUT="$(cat /kratix/input/object.yaml)"
+
+mkdir -p /kratix/output/{{ .OutputDir }}
+yq '.metadata.name = strenv(KRATIX_PROMISE_NAME) + "-" + strenv(NAME) |
+  .metadata.namespace = strenv(NAMESPACE) |
+  .spec.resourceRef.name = strenv(NAME) |
+  .spec.resourceRef.namespace = strenv(NAMESPACE) |
+  .spec.input = strenv(INPUT)' /resources/health-definition.yaml > /kratix/output/{{ .OutputDir }}/health-definition.yaml
+
+# Schedule the HealthDefinition to the Destinations set with --destination-selector
+if [ -f /resources/destination-selectors.yaml ]; then
+  cat /resources/destination-selectors.yaml >> /kratix/metadata/destination-selectors.yaml
+fi
@@ -0,0 +1,19 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs on the schedule in the HealthDefinition, on the Destination the
+# resource was scheduled to. Check the resource and write its health to
+# /kratix/output/healthrecord.yaml. The state is one of: unknown, ready,
+# healthy, unhealthy, degraded.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+namespace=$(yq '.metadata.namespace' /kratix/input/object.yaml)
+
+state="healthy"
+
+cat > /kratix/output/healthrecord.yaml <<EOF
+state: ${state}
+details:
+  message: "${name} in ${namespace} is ${state}"
+EOF
@@ -0,0 +1,16 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when a {{ .PromiseName }} request is deleted. Kratix removes
+# the manifests rendered from the chart by the instance-configure pipeline, but
+# resources created by the workloads themselves are left behind. The most
+# common are PersistentVolumeClaims created by StatefulSets; uncomment the
+# lines below to remove them. The pipeline's service account needs permission
+# to delete them, see `kratix update pipeline-rbac`.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+namespace="$(yq eval '.metadata.namespace' /kratix/input/object.yaml)"
+
+echo "Cleaning up release ${name} in ${namespace}"
+# kubectl delete persistentvolumeclaims --namespace "${namespace}" --selector "app.kubernetes.io/instance=${name}"
@@ -0,0 +1,15 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when a {{ .PromiseName }} request is deleted. Kratix removes
+# the Pulumi Stack written by the instance-configure pipeline. The Pulumi
+# Kubernetes Operator only destroys the stack's resources when the Stack sets
+# spec.destroyOnFinalize; otherwise destroy them here, for example with
+# `pulumi destroy --stack "${name}" --yes`. Nothing needs to be written to
+# /kratix/output.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+namespace="$(yq eval '.metadata.namespace' /kratix/input/object.yaml)"
+
+echo "Cleaning up Pulumi stack ${name} in ${namespace}"
@@ -0,0 +1,15 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when a {{ .PromiseName }} request is deleted. Kratix removes
+# the Terraform file written by the instance-configure pipeline from the
+# Destination, and the tool applying it (for example Flux's tf-controller or
+# Atlantis) destroys the infrastructure. Use this script for anything that must
+# happen around that, such as taking a final backup or deregistering the
+# resource from other systems. Nothing needs to be written to /kratix/output.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+namespace="$(yq eval '.metadata.namespace' /kratix/input/object.yaml)"
+
+echo "Terraform resources for ${name} in ${namespace} are destroyed when the generated module is removed"
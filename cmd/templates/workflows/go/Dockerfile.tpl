@@ -2,9 +2,9 @@ FROM golang:latest
 
 WORKDIR /scripts
 
-COPY scripts/go.mod scripts/go.sum scripts/pipeline.go ./
+COPY scripts/go.mod scripts/pipeline.go ./
 
-RUN go mod download
+RUN go mod tidy
 
 ADD resources resources
 
@@ -12,4 +12,4 @@ RUN go build -a -o /usr/bin/pipeline.go pipeline.go
 
 CMD [ "sh", "-c", "pipeline.go" ]
 
-ENTRYPOINT []
\ No newline at end of file
+ENTRYPOINT []
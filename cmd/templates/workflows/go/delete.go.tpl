@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	kratix "github.com/syntasso/kratix-go"
+)
+
+// This program runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+// documents written by the configure pipelines, so nothing needs to be written
+// to the output directory. Clean up anything created outside of Kratix here,
+// such as cloud resources, DNS records or entries in other systems.
+func main() {
+	sdk := kratix.New()
+	if sdk.IsPromiseWorkflow() {
+		fmt.Printf("Cleaning up %s", sdk.PromiseName())
+	} else {
+		resource, _ := sdk.ReadResourceInput()
+		fmt.Printf("Cleaning up %s %s", resource.GetName(), resource.GetNamespace())
+	}
+}
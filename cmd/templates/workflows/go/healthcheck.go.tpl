@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	kratix "github.com/syntasso/kratix-go"
+)
+
+// This program runs on the schedule in the HealthDefinition, on the Destination
+// the resource was scheduled to. Check the resource and write its health to
+// /kratix/output/healthrecord.yaml. The state is one of: unknown, ready,
+// healthy, unhealthy, degraded.
+func main() {
+	sdk := kratix.New()
+	resource, err := sdk.ReadResourceInput()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	state := "healthy"
+	record := fmt.Sprintf("state: %s\ndetails:\n  message: %q\n", state,
+		fmt.Sprintf("%s in %s is %s", resource.GetName(), resource.GetNamespace(), state))
+	if err := os.WriteFile("/kratix/output/healthrecord.yaml", []byte(record), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,3 @@
+FROM "{{ .Image }}"
+
+ADD resources /resources
@@ -0,0 +1,16 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+# manifests rendered from the chart by the configure pipeline, so nothing needs
+# to be written to /kratix/output. Clean up anything the chart leaves behind
+# here, such as PersistentVolumeClaims created by StatefulSets.
+
+if [ "${KRATIX_WORKFLOW_TYPE:-}" = "promise" ]; then
+  name="${KRATIX_PROMISE_NAME}"
+else
+  name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+fi
+
+echo "Cleaning up release ${name}"
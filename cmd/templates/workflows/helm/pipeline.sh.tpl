@@ -0,0 +1,17 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# Renders a Helm chart into the Kratix output directory. CHART can be a chart
+# directory copied into resources/, a repository chart reference or an OCI URL.
+CHART=${CHART:-/resources/chart}
+
+if [ "${KRATIX_WORKFLOW_TYPE:-}" = "promise" ]; then
+  name="${KRATIX_PROMISE_NAME}"
+  touch /tmp/values.yaml
+else
+  name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+  yq eval '.spec // {}' /kratix/input/object.yaml > /tmp/values.yaml
+fi
+
+helm template "${name}" "${CHART}" --values /resources/values.yaml --values /tmp/values.yaml > /kratix/output/chart.yaml
@@ -0,0 +1 @@
+# Default values passed to the chart; values from the request spec take precedence.
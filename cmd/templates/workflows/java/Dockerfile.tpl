@@ -0,0 +1,17 @@
+FROM maven:3.9-eclipse-temurin-21 AS build
+
+WORKDIR /build
+
+COPY scripts/pom.xml ./
+RUN mvn -q dependency:go-offline
+
+COPY scripts/pipeline.java ./
+RUN mvn -q package
+
+FROM eclipse-temurin:21-jre
+
+COPY --from=build /build/target/pipeline.jar /app/pipeline.jar
+ADD resources /resources
+
+CMD [ "java", "-jar", "/app/pipeline.jar" ]
+ENTRYPOINT []
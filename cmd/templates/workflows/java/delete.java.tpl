@@ -0,0 +1,26 @@
+import java.io.FileInputStream;
+import java.io.IOException;
+import java.io.InputStream;
+import java.util.Map;
+
+import org.yaml.snakeyaml.Yaml;
+
+// This program runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+// documents written by the configure pipelines, so nothing needs to be written
+// to the output directory. Clean up anything created outside of Kratix here,
+// such as cloud resources, DNS records or entries in other systems.
+class Pipeline {
+    public static void main(String[] args) throws IOException {
+        if ("promise".equals(System.getenv("KRATIX_WORKFLOW_TYPE"))) {
+            System.out.printf("Cleaning up %s%n", System.getenv("KRATIX_PROMISE_NAME"));
+            return;
+        }
+
+        try (InputStream input = new FileInputStream("/kratix/input/object.yaml")) {
+            Map<String, Object> resource = new Yaml().load(input);
+            @SuppressWarnings("unchecked")
+            Map<String, Object> metadata = (Map<String, Object>) resource.get("metadata");
+            System.out.printf("Cleaning up %s %s%n", metadata.get("name"), metadata.get("namespace"));
+        }
+    }
+}
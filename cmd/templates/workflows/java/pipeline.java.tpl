@@ -0,0 +1,22 @@
+import java.io.FileInputStream;
+import java.io.IOException;
+import java.io.InputStream;
+import java.util.Map;
+
+import org.yaml.snakeyaml.Yaml;
+
+class Pipeline {
+    public static void main(String[] args) throws IOException {
+        if ("promise".equals(System.getenv("KRATIX_WORKFLOW_TYPE"))) {
+            System.out.printf("Hello from %s%n", System.getenv("KRATIX_PROMISE_NAME"));
+            return;
+        }
+
+        try (InputStream input = new FileInputStream("/kratix/input/object.yaml")) {
+            Map<String, Object> resource = new Yaml().load(input);
+            @SuppressWarnings("unchecked")
+            Map<String, Object> metadata = (Map<String, Object>) resource.get("metadata");
+            System.out.printf("Hello from %s %s%n", metadata.get("name"), metadata.get("namespace"));
+        }
+    }
+}
@@ -0,0 +1,11 @@
+FROM "alpine"
+
+RUN apk update && apk add --no-cache yq kustomize
+
+ADD scripts/pipeline.sh /usr/bin/pipeline.sh
+ADD resources resources
+
+RUN chmod +x /usr/bin/pipeline.sh
+
+CMD [ "sh", "-c", "pipeline.sh" ]
+ENTRYPOINT []
@@ -0,0 +1,12 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# This script runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+# manifests rendered from the kustomization by the configure pipeline, so
+# nothing needs to be written to /kratix/output. Clean up anything those
+# manifests leave behind here, such as PersistentVolumeClaims.
+
+name="$(yq eval '.metadata.name' /kratix/input/object.yaml)"
+
+echo "Cleaning up ${name}"
@@ -0,0 +1,8 @@
+#!/usr/bin/env sh
+
+set -xe
+
+# Renders the kustomization in /resources into the Kratix output directory.
+# Add your manifests to the resources directory and list them in
+# resources/kustomization.yaml.
+kustomize build /resources > /kratix/output/resources.yaml
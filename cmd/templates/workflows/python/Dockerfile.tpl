@@ -1,8 +1,10 @@
 FROM python:3.12-slim
 
-RUN python -m pip install kratix-sdk
-
 WORKDIR /app
+COPY scripts/requirements.txt /app/requirements.txt
+RUN python -m pip install --no-cache-dir -r /app/requirements.txt
+
 COPY scripts/pipeline.py /app/pipeline.py
+ADD resources /resources
 
-ENTRYPOINT ["python", "-u", "/app/pipeline.py"]
\ No newline at end of file
+ENTRYPOINT ["python", "-u", "/app/pipeline.py"]
@@ -0,0 +1,16 @@
+import kratix_sdk as ks
+
+# This script runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+# documents written by the configure pipelines, so nothing needs to be written
+# to the output directory. Clean up anything created outside of Kratix here,
+# such as cloud resources, DNS records or entries in other systems.
+def main():
+    sdk = ks.KratixSDK()
+    if sdk.is_promise_workflow():
+        print(f'Cleaning up {sdk.promise_name()}')
+    else:
+        resource = sdk.read_resource_input()
+        print(f'Cleaning up {resource.get_name()} {resource.get_namespace()}')
+
+if __name__ == '__main__':
+    main()
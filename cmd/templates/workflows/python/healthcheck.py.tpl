@@ -0,0 +1,17 @@
+import kratix_sdk as ks
+
+# This script runs on the schedule in the HealthDefinition, on the Destination the
+# resource was scheduled to. Check the resource and write its health to
+# /kratix/output/healthrecord.yaml. The state is one of: unknown, ready,
+# healthy, unhealthy, degraded.
+def main():
+    sdk = ks.KratixSDK()
+    resource = sdk.read_resource_input()
+
+    state = 'healthy'
+    message = f'{resource.get_name()} in {resource.get_namespace()} is {state}'
+    with open('/kratix/output/healthrecord.yaml', 'w') as f:
+        f.write(f'state: {state}\ndetails:\n  message: "{message}"\n')
+
+if __name__ == '__main__':
+    main()
@@ -0,0 +1 @@
+kratix-sdk
@@ -0,0 +1,11 @@
+[package]
+name = "pipeline"
+version = "0.1.0"
+edition = "2021"
+
+[[bin]]
+name = "pipeline"
+path = "pipeline.rs"
+
+[dependencies]
+serde_yaml = "0.9"
@@ -0,0 +1,14 @@
+FROM rust:1 AS build
+
+WORKDIR /build
+
+COPY scripts/Cargo.toml scripts/pipeline.rs ./
+RUN cargo build --release
+
+FROM debian:bookworm-slim
+
+COPY --from=build /build/target/release/pipeline /usr/bin/pipeline
+ADD resources /resources
+
+CMD [ "pipeline" ]
+ENTRYPOINT []
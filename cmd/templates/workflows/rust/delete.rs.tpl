@@ -0,0 +1,23 @@
+use std::env;
+use std::fs;
+
+// This program runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+// documents written by the configure pipelines, so nothing needs to be written
+// to the output directory. Clean up anything created outside of Kratix here,
+// such as cloud resources, DNS records or entries in other systems.
+fn main() -> Result<(), Box<dyn std::error::Error>> {
+    if env::var("KRATIX_WORKFLOW_TYPE").as_deref() == Ok("promise") {
+        println!("Cleaning up {}", env::var("KRATIX_PROMISE_NAME").unwrap_or_default());
+        return Ok(());
+    }
+
+    let input = fs::read_to_string("/kratix/input/object.yaml")?;
+    let resource: serde_yaml::Value = serde_yaml::from_str(&input)?;
+    let metadata = &resource["metadata"];
+    println!(
+        "Cleaning up {} {}",
+        metadata["name"].as_str().unwrap_or_default(),
+        metadata["namespace"].as_str().unwrap_or_default()
+    );
+    Ok(())
+}
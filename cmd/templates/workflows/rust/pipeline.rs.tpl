@@ -0,0 +1,19 @@
+use std::env;
+use std::fs;
+
+fn main() -> Result<(), Box<dyn std::error::Error>> {
+    if env::var("KRATIX_WORKFLOW_TYPE").as_deref() == Ok("promise") {
+        println!("Hello from {}", env::var("KRATIX_PROMISE_NAME").unwrap_or_default());
+        return Ok(());
+    }
+
+    let input = fs::read_to_string("/kratix/input/object.yaml")?;
+    let resource: serde_yaml::Value = serde_yaml::from_str(&input)?;
+    let metadata = &resource["metadata"];
+    println!(
+        "Hello from {} {}",
+        metadata["name"].as_str().unwrap_or_default(),
+        metadata["namespace"].as_str().unwrap_or_default()
+    );
+    Ok(())
+}
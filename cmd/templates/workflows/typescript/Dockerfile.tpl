@@ -0,0 +1,14 @@
+FROM node:22-slim
+
+WORKDIR /app
+
+COPY scripts/package.json scripts/tsconfig.json ./
+RUN npm install
+
+COPY scripts/pipeline.ts ./
+RUN npm run build && npm prune --omit=dev
+
+ADD resources /resources
+
+CMD [ "node", "/app/dist/pipeline.js" ]
+ENTRYPOINT []
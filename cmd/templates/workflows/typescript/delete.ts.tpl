@@ -0,0 +1,20 @@
+import * as fs from "fs";
+import { parse } from "yaml";
+
+const inputDir = process.env.KRATIX_INPUT_DIR ?? "/kratix/input";
+
+// This script runs when the {{ .Lifecycle }} is deleted. Kratix removes the
+// documents written by the configure pipelines, so nothing needs to be written
+// to the output directory. Clean up anything created outside of Kratix here,
+// such as cloud resources, DNS records or entries in other systems.
+function main(): void {
+  if (process.env.KRATIX_WORKFLOW_TYPE === "promise") {
+    console.log(`Cleaning up ${process.env.KRATIX_PROMISE_NAME}`);
+    return;
+  }
+
+  const resource = parse(fs.readFileSync(`${inputDir}/object.yaml`, "utf8"));
+  console.log(`Cleaning up ${resource.metadata.name} ${resource.metadata.namespace}`);
+}
+
+main();
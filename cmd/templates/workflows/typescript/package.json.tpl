@@ -0,0 +1,17 @@
+{
+  "name": "pipeline",
+  "version": "0.1.0",
+  "private": true,
+  "main": "dist/pipeline.js",
+  "scripts": {
+    "build": "tsc",
+    "start": "node dist/pipeline.js"
+  },
+  "dependencies": {
+    "yaml": "^2.5.0"
+  },
+  "devDependencies": {
+    "@types/node": "^22.0.0",
+    "typescript": "^5.5.0"
+  }
+}
@@ -0,0 +1,16 @@
+import * as fs from "fs";
+import { parse } from "yaml";
+
+const inputDir = process.env.KRATIX_INPUT_DIR ?? "/kratix/input";
+
+function main(): void {
+  if (process.env.KRATIX_WORKFLOW_TYPE === "promise") {
+    console.log(`Hello from ${process.env.KRATIX_PROMISE_NAME}`);
+    return;
+  }
+
+  const resource = parse(fs.readFileSync(`${inputDir}/object.yaml`, "utf8"));
+  console.log(`Hello from ${resource.metadata.name} ${resource.metadata.namespace}`);
+}
+
+main();
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	containerutils "github.com/syntasso/kratix-cli/cmd/container_utils"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+)
+
+var testPipelineCmd = &cobra.Command{
+	Use:   "pipeline LIFECYCLE/ACTION/PIPELINE-NAME",
+	Short: "Runs the containers of a pipeline locally",
+	Long: `Runs the containers of a pipeline locally, in order, with the given input
+mounted at /kratix/input/object.yaml. The files the containers write to
+/kratix/output and /kratix/metadata are kept in the output directory.
+
+The containers run the images in the Promise; build them first with
+'kratix build container'.`,
+	Example: `  # run the resource configure pipeline against example-resource.yaml
+  kratix test pipeline resource/configure/instance
+
+  # run the health check against another resource request
+  kratix test pipeline resource/healthcheck/instance --input my-request.yaml
+
+  # keep the output in ./out
+  kratix test pipeline resource/configure/instance --output out`,
+	RunE: TestPipeline,
+	Args: cobra.ExactArgs(1),
+}
+
+var testPipelineInput, testPipelineOutput, testPipelineEngine, testPipelinePromiseName string
+
+func init() {
+	testCmd.AddCommand(testPipelineCmd)
+	testPipelineCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read the Promise from. Default to current working directory.")
+	testPipelineCmd.Flags().StringVar(&testPipelineInput, "input", "", "Object passed to the pipeline. Defaults to example-resource.yaml for resource workflows and promise.yaml for promise workflows.")
+	testPipelineCmd.Flags().StringVarP(&testPipelineOutput, "output", "o", "", "Directory to write the pipeline output to. Defaults to a new temporary directory.")
+	testPipelineCmd.Flags().StringVarP(&testPipelineEngine, "engine", "e", "docker", "Container engine used to run the containers. One of: docker, podman.")
+	testPipelineCmd.Flags().StringVar(&testPipelinePromiseName, "promise-name", "", "Name of the Promise. Defaults to the name in promise.yaml; required for Promises generated with --split.")
+}
+
+func TestPipeline(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validateEngine(testPipelineEngine); err != nil {
+		return err
+	}
+
+	pipeline, promiseName, err := loadPipelineToRun(dir, c)
+	if err != nil {
+		return err
+	}
+
+	input := testPipelineInput
+	if input == "" {
+		input = filepath.Join(dir, resourceFileName)
+		if c.Lifecycle == "promise" {
+			input = filepath.Join(dir, promiseFileName)
+		}
+	}
+	inputBytes, err := os.ReadFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read pipeline input: %w", err)
+	}
+
+	outputDir := testPipelineOutput
+	if outputDir == "" {
+		outputDir, err = os.MkdirTemp("", "kratix-test-pipeline-")
+		if err != nil {
+			return err
+		}
+	}
+
+	volumes := map[string]string{}
+	for _, volume := range []string{"input", "output", "metadata"} {
+		volumes[volume], err = filepath.Abs(filepath.Join(outputDir, volume))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(volumes[volume], os.ModePerm); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(volumes["input"], "object.yaml"), inputBytes, filePerm); err != nil {
+		return err
+	}
+
+	runOpts := &containerutils.BuildContainerOptions{Engine: testPipelineEngine}
+	for _, container := range pipeline.Spec.Containers {
+		envvars := []string{
+			"KRATIX_WORKFLOW_TYPE=" + c.Lifecycle,
+			"KRATIX_WORKFLOW_ACTION=" + c.Action,
+			"KRATIX_PROMISE_NAME=" + promiseName,
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				fmt.Printf("Skipping env var %s of container %s: values from Secrets are not available locally\n", env.Name, container.Name)
+				continue
+			}
+			envvars = append(envvars, fmt.Sprintf("%s=%s", env.Name, env.Value))
+		}
+
+		fmt.Printf("Running container %s (%s)...\n", container.Name, container.Image)
+		if err := containerutils.ForkRunCommand(runOpts, container.Image, volumes["input"], volumes["output"], volumes["metadata"], envvars, container.Command, container.Args); err != nil {
+			return fmt.Errorf("container %s failed: %w", container.Name, err)
+		}
+	}
+
+	fmt.Printf("Pipeline %s/%s/%s completed; output written to %s\n", c.Lifecycle, c.Action, c.Pipeline, outputDir)
+
+	if c.Action == healthCheckAction {
+		healthRecord, err := os.ReadFile(filepath.Join(volumes["output"], "healthrecord.yaml"))
+		if err != nil {
+			return fmt.Errorf("health check did not write /kratix/output/healthrecord.yaml")
+		}
+		fmt.Printf("Health record:\n%s", healthRecord)
+	}
+	return nil
+}
+
+// loadPipelineToRun returns the pipeline to run and the name of its Promise.
+// The resource health check pipeline is read from the Promise's health
+// definition.
+func loadPipelineToRun(promiseDir string, c *pipelineutils.PipelineCmdArgs) (v1alpha1.Pipeline, string, error) {
+	if c.Lifecycle == "resource" && c.Action == healthCheckAction {
+		check, err := loadHealthCheck(promiseDir)
+		if err != nil {
+			return v1alpha1.Pipeline{}, "", err
+		}
+		if check.definition == nil || check.definition.Spec.Workflow.GetName() != c.Pipeline {
+			return v1alpha1.Pipeline{}, "", fmt.Errorf("pipeline %s not found in the health definition; add one with 'kratix add health-check'", c.Pipeline)
+		}
+		promiseName := testPipelinePromiseName
+		if promiseName == "" {
+			promiseName = check.definition.Spec.PromiseRef.Name
+		}
+		return check.definition.Spec.Workflow, promiseName, nil
+	}
+
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return v1alpha1.Pipeline{}, "", err
+	}
+	w, err := loadWorkflowFile(promiseDir, c)
+	if err != nil {
+		return v1alpha1.Pipeline{}, "", err
+	}
+	idx, err := w.pipelineIdx(c.Pipeline)
+	if err != nil {
+		return v1alpha1.Pipeline{}, "", err
+	}
+	promiseName, err := resolvePromiseName(promiseDir, testPipelinePromiseName)
+	if err != nil {
+		return v1alpha1.Pipeline{}, "", err
+	}
+	return w.pipelines[idx], promiseName, nil
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -69,12 +68,16 @@ func init() {
 }
 
 func UpdateAPI(cmd *cobra.Command, args []string) error {
+	if err := validateAPIValues(group, kind, apiVersion, plural); err != nil {
+		return err
+	}
+
 	var crd apiextensionsv1.CustomResourceDefinition
 	var promise v1alpha1.Promise
 
 	var splitFile bool
 	filePath := filepath.Join(dir, apiFileName)
-	if _, foundErr := os.Stat(filePath); foundErr == nil {
+	if promiseFiles.exists(filePath) {
 		var err error
 		splitFile = true
 		crd, err = promiseutils.LoadCRD(dir)
@@ -83,7 +86,7 @@ func UpdateAPI(cmd *cobra.Command, args []string) error {
 		}
 	} else {
 		filePath = filepath.Join(dir, promiseFileName)
-		promiseBytes, err := os.ReadFile(filePath)
+		promiseBytes, err := promiseFiles.readFile(filePath)
 		if err != nil {
 			return fmt.Errorf("failed to find %s or %s in directory. Please run 'kratix init promise' first: %s", apiFileName, promiseFileName, err)
 		}
@@ -111,7 +114,7 @@ func UpdateAPI(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if err = os.WriteFile(filePath, bytes, filePerm); err != nil {
+	if err = promiseFiles.writeFile(filePath, bytes); err != nil {
 		return err
 	}
 
@@ -219,7 +222,7 @@ func updateGVK(crd *apiextensionsv1.CustomResourceDefinition) {
 
 func updateExampleResource(crd *apiextensionsv1.CustomResourceDefinition) error {
 	rrFilePath := filepath.Join(dir, resourceFileName)
-	rrBytes, err := os.ReadFile(rrFilePath)
+	rrBytes, err := promiseFiles.readFile(rrFilePath)
 	if err != nil {
 		return err
 	}
@@ -234,7 +237,7 @@ func updateExampleResource(crd *apiextensionsv1.CustomResourceDefinition) error
 	if err != nil {
 		return err
 	}
-	if err = os.WriteFile(rrFilePath, updatedRR, filePerm); err != nil {
+	if err = promiseFiles.writeFile(rrFilePath, updatedRR); err != nil {
 		return err
 	}
 	fmt.Println("Example resource updated")
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+)
+
+var updateContainerCmd = &cobra.Command{
+	Use:   "container LIFECYCLE/ACTION/PIPELINE-NAME --name CONTAINER-NAME",
+	Short: "Command to update a container and the pipeline it runs in",
+	Long:  "Command to update a container and the pipeline it runs in. Values ending in '-' remove the named env var, volume mount or volume.",
+	Example: `  # set an environment variable and read another from a Secret
+  kratix update container resource/configure/instance --name create-db --env LOG_LEVEL=debug --env-from-secret db-creds:password=DB_PASSWORD
+
+  # remove an environment variable
+  kratix update container resource/configure/instance --name create-db --env LOG_LEVEL-
+
+  # mount a Secret into the container
+  kratix update container resource/configure/instance --name create-db --volume creds:secret:db-creds --volume-mount creds:/creds:ro
+
+  # change the image and the service account of the pipeline
+  kratix update container resource/configure/instance --name create-db --image myorg/create-db:v2 --service-account db-admin`,
+	RunE: UpdateContainer,
+	Args: cobra.ExactArgs(1),
+}
+
+var updateContainerName, updateContainerImage string
+
+func init() {
+	updateCmd.AddCommand(updateContainerCmd)
+	updateContainerCmd.Flags().StringVarP(&updateContainerName, "name", "n", "", "The name of the container to update.")
+	updateContainerCmd.Flags().StringVarP(&updateContainerImage, "image", "i", "", "The image used by this container.")
+	updateContainerCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
+	addContainerOptionFlags(updateContainerCmd)
+	updateContainerCmd.MarkFlagRequired("name")
+}
+
+func UpdateContainer(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
+	}
+
+	w, err := loadWorkflowFile(dir, c)
+	if err != nil {
+		return err
+	}
+
+	pipelineIdx, containerIdx, err := w.containerIdx(c.Pipeline, updateContainerName)
+	if err != nil {
+		return err
+	}
+
+	pipeline := &w.pipelines[pipelineIdx]
+	container := &pipeline.Spec.Containers[containerIdx]
+	if updateContainerImage != "" {
+		container.Image = updateContainerImage
+	}
+	if err := containerOpts.apply(container); err != nil {
+		return err
+	}
+	if err := pipelineOpts.apply(pipeline); err != nil {
+		return err
+	}
+
+	if err := w.write(); err != nil {
+		return err
+	}
+	fmt.Printf("Updated container %s in pipeline %s/%s/%s\n", updateContainerName, c.Lifecycle, c.Action, c.Pipeline)
+	return nil
+}
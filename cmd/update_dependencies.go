@@ -53,7 +53,7 @@ func updateDependencies(cmd *cobra.Command, args []string) error {
 
 	switch mode {
 	case "split":
-		err = os.WriteFile(filepath.Join(dir, dependenciesFileName), depBytes, filePerm)
+		err = promiseFiles.writeFile(filepath.Join(dir, dependenciesFileName), depBytes)
 	case "flat":
 		err = updatePromiseDependencies(dependencies)
 	}
@@ -66,8 +66,7 @@ func updateDependencies(cmd *cobra.Command, args []string) error {
 }
 
 func promiseFileMode() (mode string, fileToUpdate string) {
-	_, dependencyFileErr := os.Stat(filepath.Join(dir, dependenciesFileName))
-	if _, promiseErr := os.Stat(filepath.Join(dir, promiseFileName)); os.IsNotExist(dependencyFileErr) && promiseErr == nil {
+	if !promiseFiles.exists(filepath.Join(dir, dependenciesFileName)) && promiseFiles.exists(filepath.Join(dir, promiseFileName)) {
 		return "flat", promiseFileName
 	}
 	return "split", dependenciesFileName
@@ -158,7 +157,7 @@ func extractDepFromFile(fileName string) ([]v1alpha1.Dependency, error) {
 func getPromise(filePath string) (v1alpha1.Promise, error) {
 	var promiseBytes []byte
 	var err error
-	if promiseBytes, err = os.ReadFile(filePath); err != nil {
+	if promiseBytes, err = promiseFiles.readFile(filePath); err != nil {
 		return v1alpha1.Promise{}, err
 	}
 
@@ -179,7 +178,7 @@ func updatePromiseDependencies(dependencies []v1alpha1.Dependency) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(dir, promiseFileName), bytes, filePerm)
+	return promiseFiles.writeFile(filepath.Join(dir, promiseFileName), bytes)
 }
 
 func addDepsAsWorkflow(dependenciesDir, containerName string) error {
@@ -206,14 +205,14 @@ func addDepsAsWorkflow(dependenciesDir, containerName string) error {
 	}
 
 	pipelineScriptContent := "#!/usr/bin/env sh\n\ncp /resources/* /kratix/output"
-	if err := os.WriteFile(filepath.Join(scriptsDir, "pipeline.sh"), []byte(pipelineScriptContent), filePerm); err != nil {
+	if err := promiseFiles.writeFile(filepath.Join(scriptsDir, "pipeline.sh"), []byte(pipelineScriptContent)); err != nil {
 		return err
 	}
 
 	mode, _ := promiseFileMode()
 	switch mode {
 	case "split":
-		err = os.Remove(filepath.Join(dir, dependenciesFileName))
+		err = promiseFiles.removeAll(filepath.Join(dir, dependenciesFileName))
 	case "flat":
 		err = updatePromiseDependencies([]v1alpha1.Dependency{})
 	}
@@ -242,7 +241,7 @@ func copyFiles(src, dest string) error {
 
 		for _, f := range files {
 			if f.IsDir() {
-				if err := os.Mkdir(filepath.Join(dest, f.Name()), 0755); err != nil {
+				if err := promiseFiles.mkdirAll(filepath.Join(dest, f.Name())); err != nil {
 					return err
 				}
 				if err := copyFiles(filepath.Join(src, f.Name()), filepath.Join(dest, f.Name())); err != nil {
@@ -271,7 +270,7 @@ func writeToFile(src string, dest string, fileName string) error {
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(dest, fileName), fileContents, 0644); err != nil {
+	if err := promiseFiles.writeFile(filepath.Join(dest, fileName), fileContents); err != nil {
 		return err
 	}
 	return nil
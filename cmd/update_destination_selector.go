@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/syntasso/kratix/api/v1alpha1"
-	"os"
 	"path/filepath"
 	"sigs.k8s.io/yaml"
 	"strings"
@@ -54,7 +53,7 @@ func UpdateSelector(cmd *cobra.Command, args []string) error {
 	if promiseBytes, err = yaml.Marshal(promise); err != nil {
 		return err
 	}
-	if err = os.WriteFile(filepath.Join(dir, "promise.yaml"), promiseBytes, filePerm); err != nil {
+	if err = promiseFiles.writeFile(filepath.Join(dir, "promise.yaml"), promiseBytes); err != nil {
 		return err
 	}
 
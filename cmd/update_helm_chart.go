@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix-cli/internal"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const updateHelmChartLongHelp = `Preview: Update the Helm chart version of a Promise generated with 'kratix init helm-promise'.
+
+The CHART_VERSION of the resource configure pipeline is set to the new version,
+and the Promise API is updated with the values added, removed and changed by the
+new version of the chart. Properties added to the API by hand are kept.
+
+The values filtered out by --include-values and --exclude-values when the
+Promise was generated stay filtered out. When the Promise was generated
+without a chart version, values removed from the chart are left in the API.
+
+This command is in preview, not supported under SLAs, and may change or break without notice.`
+
+var updateHelmChartCmd = &cobra.Command{
+	Use:   "helm-chart --version CHART-VERSION",
+	Short: "Preview: Update the Helm chart version of a Promise",
+	Long:  updateHelmChartLongHelp,
+	Example: `  # update the Promise in the current directory to version 1.2.0 of its chart
+  kratix update helm-chart --version 1.2.0`,
+	RunE: UpdateHelmChart,
+	Args: cobra.NoArgs,
+}
+
+var helmChartVersion string
+
+func init() {
+	updateCmd.AddCommand(updateHelmChartCmd)
+	updateHelmChartCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read Promise from")
+	updateHelmChartCmd.Flags().StringVarP(&helmChartVersion, "version", "v", "", "The Helm chart version to update to")
+	addHelmAuthFlags(updateHelmChartCmd)
+	updateHelmChartCmd.MarkFlagRequired("version")
+}
+
+func UpdateHelmChart(cmd *cobra.Command, args []string) error {
+	printPreviewWarning()
+	w, err := loadWorkflowFile(dir, &pipelineutils.PipelineCmdArgs{Lifecycle: "resource", Action: "configure"})
+	if err != nil {
+		return err
+	}
+	container := helmResourceConfigureContainer(w)
+	if container == nil {
+		return fmt.Errorf("no %s container found in the resource configure workflow; only Promises generated with 'kratix init helm-promise' can be updated", helmResourceConfigureImage)
+	}
+
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	if strings.HasPrefix(env["CHART_URL"], vendoredChartDir) {
+		return fmt.Errorf("the chart is vendored into the pipeline image; run 'kratix regenerate -- --chart-version %s' for charts from --chart-url, or 'kratix regenerate -- --chart-path NEW-CHART-PATH' for local charts", helmChartVersion)
+	}
+	var fixedValues map[string]any
+	if err := yaml.Unmarshal([]byte(env["FIXED_VALUES"]), &fixedValues); err != nil {
+		return fmt.Errorf("failed to parse FIXED_VALUES: %w", err)
+	}
+
+	if err := readPasswordStdin(); err != nil {
+		return err
+	}
+
+	// Promises generated before `kratix regenerate` have no generator config
+	// to keep the chart version in
+	var config generatorConfig
+	configPath := filepath.Join(dir, generatorDirName, generatorFileName)
+	hasGeneratorConfig := promiseFiles.exists(configPath)
+	if hasGeneratorConfig {
+		if config, err = loadGeneratorConfig(dir); err != nil {
+			return err
+		}
+	}
+	include, exclude := config.Flags["include-values"], config.Flags["exclude-values"]
+
+	newChart, err := fetchChart(env["CHART_URL"], env["CHART_NAME"], helmChartVersion)
+	if err != nil {
+		return err
+	}
+	newSchema, unmatched, err := helmValuesSchema(newChart, fixedValues, include, exclude)
+	if err != nil {
+		return err
+	}
+	printUnmatchedValuesPatterns(newChart, unmatched)
+
+	var oldSchema *apiextensionsv1.JSONSchemaProps
+	if oldVersion := env["CHART_VERSION"]; oldVersion != "" {
+		oldChart, err := fetchChart(env["CHART_URL"], env["CHART_NAME"], oldVersion)
+		if err != nil {
+			return err
+		}
+		if oldSchema, _, err = helmValuesSchema(oldChart, fixedValues, include, exclude); err != nil {
+			return err
+		}
+	}
+
+	crd, apiPath, err := loadPromiseAPI(w)
+	if err != nil {
+		return err
+	}
+	if len(crd.Spec.Versions) == 0 || crd.Spec.Versions[0].Schema == nil || crd.Spec.Versions[0].Schema.OpenAPIV3Schema == nil {
+		return fmt.Errorf("%s has no API schema to update", apiPath)
+	}
+	apiSchema := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+	specSchema := apiSchema.Properties["spec"]
+	if oldSchema != nil {
+		printHelmValuesChanges(internal.DiffHelmValuesSchemas(oldSchema, newSchema))
+	} else {
+		// without the old chart, values missing from the new chart may have
+		// been added by hand
+		changes := internal.DiffHelmValuesSchemas(&specSchema, newSchema)
+		printHelmValuesChanges(slices.DeleteFunc(changes, func(change internal.HelmValuesChange) bool {
+			return change.Change == internal.HelmValueRemoved
+		}))
+	}
+	internal.MergeHelmValuesSchemas(&specSchema, oldSchema, newSchema)
+	if apiSchema.Properties == nil {
+		apiSchema.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+	}
+	apiSchema.Properties["spec"] = specSchema
+
+	// the API and the workflow are written together, so that a failure
+	// cannot leave the pipeline on a chart version the API does not match
+	container.Env = setEnvVar(container.Env, corev1.EnvVar{Name: "CHART_VERSION", Value: helmChartVersion})
+	if err := writePromiseAPI(w, crd, apiPath); err != nil {
+		return err
+	}
+
+	if hasGeneratorConfig {
+		if config.Flags == nil {
+			config.Flags = map[string][]string{}
+		}
+		config.Flags["chart-version"] = []string{helmChartVersion}
+		configBytes, err := yaml.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if err := promiseFiles.writeFile(configPath, configBytes); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Promise updated to version %s of chart %s\n", helmChartVersion, newChart.Name())
+	return nil
+}
+
+// helmResourceConfigureContainer returns the container rendering the chart
+// in the resource configure workflow, or nil if there is none. Containers
+// vendoring the chart run an image built on top of helmResourceConfigureImage,
+// so they are found by their vendored CHART_URL.
+func helmResourceConfigureContainer(w *workflowFile) *v1alpha1.Container {
+	for i := range w.pipelines {
+		containers := w.pipelines[i].Spec.Containers
+		for j := range containers {
+			if strings.HasPrefix(containers[j].Image, helmResourceConfigureImage+":") || containers[j].Image == vendoredChartImage {
+				return &containers[j]
+			}
+			for _, env := range containers[j].Env {
+				if env.Name == "CHART_URL" && strings.HasPrefix(env.Value, vendoredChartDir) {
+					return &containers[j]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// loadPromiseAPI returns the API of the Promise from api.yaml, or from the
+// Promise the workflow file was loaded from, along with the file it is in.
+func loadPromiseAPI(w *workflowFile) (*apiextensionsv1.CustomResourceDefinition, string, error) {
+	var crd apiextensionsv1.CustomResourceDefinition
+	if w.split {
+		apiPath := filepath.Join(dir, apiFileName)
+		apiBytes, err := promiseFiles.readFile(apiPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := yaml.Unmarshal(apiBytes, &crd); err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", apiPath, err)
+		}
+		return &crd, apiPath, nil
+	}
+
+	if w.promise.Spec.API == nil {
+		return nil, "", fmt.Errorf("%s has no API", w.path)
+	}
+	if err := yaml.Unmarshal(w.promise.Spec.API.Raw, &crd); err != nil {
+		return nil, "", fmt.Errorf("failed to parse the API in %s: %w", w.path, err)
+	}
+	return &crd, w.path, nil
+}
+
+// writePromiseAPI writes the API to api.yaml, or into the Promise of the
+// workflow file, and then writes the workflow file.
+func writePromiseAPI(w *workflowFile, crd *apiextensionsv1.CustomResourceDefinition, path string) error {
+	jsonBytes, err := json.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	apiContents := &runtime.RawExtension{Raw: jsonBytes}
+	if !w.split {
+		w.promise.Spec.API = apiContents
+		return w.write()
+	}
+
+	bytes, err := yaml.Marshal(apiContents)
+	if err != nil {
+		return err
+	}
+	if err := promiseFiles.writeFile(path, bytes); err != nil {
+		return err
+	}
+	return w.write()
+}
+
+func printHelmValuesChanges(changes []internal.HelmValuesChange) {
+	if len(changes) == 0 {
+		fmt.Println("No changes to the chart's values")
+		return
+	}
+	symbols := map[string]string{
+		internal.HelmValueAdded:   "+",
+		internal.HelmValueRemoved: "-",
+		internal.HelmValueChanged: "~",
+	}
+	fmt.Println("Changes to the chart's values:")
+	for _, change := range changes {
+		fmt.Printf("  %s %s\n", symbols[change.Change], change.Path)
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+)
+
+var updatePipelineCmd = &cobra.Command{
+	Use:   "pipeline LIFECYCLE/ACTION/PIPELINE-NAME",
+	Short: "Command to reorder, rename and move pipelines and their containers",
+	Long: `Command to reorder, rename and move pipelines and their containers. The workflows/ directory is kept in sync with the Promise.
+
+Pipelines in a workflow run in order, as do the containers in a pipeline. With --move-container, --before and --after
+reorder the containers of the pipeline; without it, they reorder the pipeline itself in its workflow, or in the
+workflow given with --move-to.`,
+	Example: `  # run the container 'create-db' before the container 'notify'
+  kratix update pipeline resource/configure/instance --move-container create-db --before notify
+
+  # run the container 'notify' last
+  kratix update pipeline resource/configure/instance --move-container notify --after create-db
+
+  # run the pipeline 'database' before the pipeline 'instance'
+  kratix update pipeline resource/configure/database --before instance
+
+  # rename the pipeline 'instance' to 'database'
+  kratix update pipeline resource/configure/instance --rename database
+
+  # move the pipeline to the promise configure workflow
+  kratix update pipeline resource/configure/instance --move-to promise/configure`,
+	RunE: UpdatePipeline,
+	Args: cobra.ExactArgs(1),
+}
+
+var moveContainer, moveBefore, moveAfter, renamePipelineTo, movePipelineTo string
+
+func init() {
+	updateCmd.AddCommand(updatePipelineCmd)
+	updatePipelineCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
+	updatePipelineCmd.Flags().StringVar(&moveContainer, "move-container", "", "Name of the container to move. Requires --before or --after.")
+	updatePipelineCmd.Flags().StringVar(&moveBefore, "before", "", "Move the container before this container, or without --move-container, the pipeline before this pipeline.")
+	updatePipelineCmd.Flags().StringVar(&moveAfter, "after", "", "Move the container after this container, or without --move-container, the pipeline after this pipeline.")
+	updatePipelineCmd.Flags().StringVar(&renamePipelineTo, "rename", "", "New name for the pipeline.")
+	updatePipelineCmd.Flags().StringVar(&movePipelineTo, "move-to", "", "Move the pipeline to another workflow, in the format LIFECYCLE/ACTION.")
+	updatePipelineCmd.MarkFlagsMutuallyExclusive("before", "after")
+}
+
+func UpdatePipeline(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
+	}
+
+	reorder := moveBefore != "" || moveAfter != ""
+	if moveContainer == "" && renamePipelineTo == "" && movePipelineTo == "" && !reorder {
+		return fmt.Errorf("nothing to update: set one of --move-container, --before, --after, --rename or --move-to")
+	}
+	if moveContainer != "" && !reorder {
+		return fmt.Errorf("--move-container requires --before or --after")
+	}
+	reorderPipeline := moveContainer == "" && reorder
+
+	target := &pipelineutils.PipelineCmdArgs{Lifecycle: c.Lifecycle, Action: c.Action, Pipeline: c.Pipeline}
+	if renamePipelineTo != "" {
+		target.Pipeline = renamePipelineTo
+	}
+	if movePipelineTo != "" {
+		target, err = pipelineutils.ParsePipelineCmdArgs(movePipelineTo + "/" + target.Pipeline)
+		if err != nil {
+			return fmt.Errorf("invalid --move-to: %s, expected format: LIFECYCLE/ACTION", movePipelineTo)
+		}
+	}
+	if err := validatePipelineCmdArgs(target); err != nil {
+		return err
+	}
+
+	source, err := loadWorkflowFile(dir, c)
+	if err != nil {
+		return err
+	}
+	pipelineIdx, err := source.pipelineIdx(c.Pipeline)
+	if err != nil {
+		return err
+	}
+	pipeline := source.pipelines[pipelineIdx]
+
+	if moveContainer != "" {
+		if err := reorderContainer(&pipeline, moveContainer, moveBefore, moveAfter); err != nil {
+			return err
+		}
+	}
+
+	pipeline.SetName(target.Pipeline)
+
+	sameWorkflow := target.Lifecycle == c.Lifecycle && target.Action == c.Action
+	moved := target.Pipeline != c.Pipeline || !sameWorkflow
+	fromDir := workflowDir(dir, c.Lifecycle, c.Action, c.Pipeline, "")
+	toDir := workflowDir(dir, target.Lifecycle, target.Action, target.Pipeline, "")
+	if moved {
+		destination, err := loadWorkflowFile(dir, target)
+		if err != nil {
+			return err
+		}
+		if idx, _ := getPipelineIdx(destination.pipelines, target.Pipeline); idx != -1 {
+			return fmt.Errorf("pipeline %s already exists in the %s/%s workflow", target.Pipeline, target.Lifecycle, target.Action)
+		}
+		if pathExists(fromDir) && pathExists(toDir) {
+			return fmt.Errorf("cannot move %s: %s already exists", fromDir, toDir)
+		}
+		// check the pipeline can be reordered before anything is written
+		if reorderPipeline && !sameWorkflow {
+			if _, err := reorderPipelines(append(slices.Clone(destination.pipelines), pipeline), target.Pipeline, moveBefore, moveAfter); err != nil {
+				return err
+			}
+		}
+	}
+
+	if sameWorkflow {
+		source.pipelines[pipelineIdx] = pipeline
+		if reorderPipeline {
+			if source.pipelines, err = reorderPipelines(source.pipelines, target.Pipeline, moveBefore, moveAfter); err != nil {
+				return err
+			}
+		}
+		if err := source.write(); err != nil {
+			return err
+		}
+	} else {
+		source.pipelines = slices.Delete(source.pipelines, pipelineIdx, pipelineIdx+1)
+		if err := source.write(); err != nil {
+			return err
+		}
+
+		// the destination is loaded after the source is written as both may
+		// live in promise.yaml
+		destination, err := loadWorkflowFile(dir, target)
+		if err != nil {
+			return err
+		}
+		destination.pipelines = append(destination.pipelines, pipeline)
+		if reorderPipeline {
+			if destination.pipelines, err = reorderPipelines(destination.pipelines, target.Pipeline, moveBefore, moveAfter); err != nil {
+				return err
+			}
+		}
+		if err := destination.write(); err != nil {
+			return err
+		}
+	}
+
+	if moveContainer != "" {
+		fmt.Printf("Moved container %s in pipeline %s/%s/%s\n", moveContainer, c.Lifecycle, c.Action, c.Pipeline)
+	}
+	if reorderPipeline {
+		fmt.Printf("Moved pipeline %s in the %s/%s workflow\n", target.Pipeline, target.Lifecycle, target.Action)
+	}
+
+	if moved {
+		if pathExists(fromDir) {
+			if err := promiseFiles.rename(fromDir, toDir); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Moved pipeline %s/%s/%s to %s/%s/%s\n", c.Lifecycle, c.Action, c.Pipeline, target.Lifecycle, target.Action, target.Pipeline)
+	}
+	return nil
+}
+
+// reorderContainer moves the named container so that it runs immediately
+// before or after another container in the pipeline.
+func reorderContainer(pipeline *v1alpha1.Pipeline, name, before, after string) error {
+	idx := getContainerIdx(*pipeline, name)
+	if idx == -1 {
+		return fmt.Errorf("container %s not found in pipeline %s", name, pipeline.GetName())
+	}
+
+	anchor := before
+	if anchor == "" {
+		anchor = after
+	}
+	if anchor == name {
+		return fmt.Errorf("cannot move container %s relative to itself", name)
+	}
+	if getContainerIdx(*pipeline, anchor) == -1 {
+		return fmt.Errorf("container %s not found in pipeline %s", anchor, pipeline.GetName())
+	}
+
+	container := pipeline.Spec.Containers[idx]
+	pipeline.Spec.Containers = slices.Delete(pipeline.Spec.Containers, idx, idx+1)
+
+	anchorIdx := getContainerIdx(*pipeline, anchor)
+	if after != "" {
+		anchorIdx++
+	}
+	pipeline.Spec.Containers = slices.Insert(pipeline.Spec.Containers, anchorIdx, container)
+	return nil
+}
+
+// reorderPipelines moves the named pipeline so that it runs immediately before
+// or after another pipeline of the workflow.
+func reorderPipelines(pipelines []v1alpha1.Pipeline, name, before, after string) ([]v1alpha1.Pipeline, error) {
+	idx, _ := getPipelineIdx(pipelines, name)
+	if idx == -1 {
+		return nil, fmt.Errorf("pipeline %s not found", name)
+	}
+
+	anchor := before
+	if anchor == "" {
+		anchor = after
+	}
+	if anchor == name {
+		return nil, fmt.Errorf("cannot move pipeline %s relative to itself", name)
+	}
+	if anchorIdx, _ := getPipelineIdx(pipelines, anchor); anchorIdx == -1 {
+		return nil, fmt.Errorf("pipeline %s not found in the workflow", anchor)
+	}
+
+	pipeline := pipelines[idx]
+	pipelines = slices.Delete(pipelines, idx, idx+1)
+
+	anchorIdx, _ := getPipelineIdx(pipelines, anchor)
+	if after != "" {
+		anchorIdx++
+	}
+	return slices.Insert(pipelines, anchorIdx, pipeline), nil
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
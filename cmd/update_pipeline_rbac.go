@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+)
+
+var updatePipelineRBACCmd = &cobra.Command{
+	Use:   "pipeline-rbac LIFECYCLE/ACTION/PIPELINE-NAME",
+	Short: "Command to update the permissions granted to a pipeline",
+	Long: `Command to update the permissions granted to a pipeline's service account.
+
+Permissions are written as comma separated KEY=VALUE pairs, where KEY is one of
+apiGroups, resources, verbs, resourceNames or resourceNamespace and list values
+are separated by ';'. An empty apiGroups (or no apiGroups) means the core API group.
+Without --allow or --remove, the current permissions are printed.`,
+	Example: `  # allow the pipeline to read deployments in the namespace of the request
+  kratix update pipeline-rbac resource/configure/instance --allow "apiGroups=apps,resources=deployments,verbs=get;list"
+
+  # allow the pipeline to read secrets in the 'default' namespace
+  kratix update pipeline-rbac resource/configure/instance --allow "resources=secrets,verbs=get" --resource-namespace default
+
+  # remove every permission on deployments
+  kratix update pipeline-rbac resource/configure/instance --remove "apiGroups=apps,resources=deployments"`,
+	RunE: UpdatePipelineRBAC,
+	Args: cobra.ExactArgs(1),
+}
+
+var allowPermissions, removePermissions []string
+var permissionResourceNamespace string
+
+// knownVerbs are the verbs understood by Kubernetes RBAC
+var knownVerbs = []string{
+	"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection",
+	"use", "bind", "escalate", "impersonate", "approve", "sign", "*",
+}
+
+func init() {
+	updateCmd.AddCommand(updatePipelineRBACCmd)
+	updatePipelineRBACCmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to read promise.yaml from. Default to current working directory.")
+	updatePipelineRBACCmd.Flags().StringArrayVar(&allowPermissions, "allow", nil, "Permission to grant, as \"apiGroups=apps,resources=deployments,verbs=get;list\". Can be repeated.")
+	updatePipelineRBACCmd.Flags().StringArrayVar(&removePermissions, "remove", nil, "Remove the permissions matching every given field, as \"apiGroups=apps,resources=deployments\". Can be repeated.")
+	updatePipelineRBACCmd.Flags().StringVar(&permissionResourceNamespace, "resource-namespace", "", "Namespace the --allow permissions apply to, for those not setting resourceNamespace. Use '*' for all namespaces.")
+}
+
+func UpdatePipelineRBAC(cmd *cobra.Command, args []string) error {
+	c, err := pipelineutils.ParsePipelineCmdArgs(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validatePipelineCmdArgs(c); err != nil {
+		return err
+	}
+
+	w, err := loadWorkflowFile(dir, c)
+	if err != nil {
+		return err
+	}
+	pipelineIdx, err := w.pipelineIdx(c.Pipeline)
+	if err != nil {
+		return err
+	}
+	rbac := &w.pipelines[pipelineIdx].Spec.RBAC
+
+	if len(allowPermissions) == 0 && len(removePermissions) == 0 {
+		if len(rbac.Permissions) == 0 {
+			fmt.Printf("Pipeline %s/%s/%s has no permissions\n", c.Lifecycle, c.Action, c.Pipeline)
+		}
+		for _, permission := range rbac.Permissions {
+			fmt.Println(formatPermission(permission))
+		}
+		return nil
+	}
+
+	for _, r := range removePermissions {
+		filter, err := parsePermissionFields(r)
+		if err != nil {
+			return err
+		}
+		before := len(rbac.Permissions)
+		rbac.Permissions = slices.DeleteFunc(rbac.Permissions, func(p v1alpha1.Permission) bool {
+			return permissionMatches(p, filter)
+		})
+		if len(rbac.Permissions) == before {
+			return fmt.Errorf("no permission matching %q found in pipeline %s", r, c.Pipeline)
+		}
+	}
+
+	for _, a := range allowPermissions {
+		permission, err := parsePermission(a)
+		if err != nil {
+			return err
+		}
+		if permission.ResourceNamespace == "" {
+			permission.ResourceNamespace = permissionResourceNamespace
+		}
+		rbac.Permissions = appendPermission(rbac.Permissions, permission)
+	}
+
+	if err := w.write(); err != nil {
+		return err
+	}
+	fmt.Printf("Pipeline %s/%s/%s permissions updated\n", c.Lifecycle, c.Action, c.Pipeline)
+	return nil
+}
+
+// parsePermission parses and validates a permission in the form
+// "apiGroups=apps,resources=deployments,verbs=get;list".
+func parsePermission(s string) (v1alpha1.Permission, error) {
+	permission, err := parsePermissionFields(s)
+	if err != nil {
+		return permission, err
+	}
+
+	if permission.APIGroups == nil {
+		permission.APIGroups = []string{""}
+	}
+	if len(permission.Resources) == 0 || len(permission.Verbs) == 0 {
+		return permission, fmt.Errorf("invalid permission %q: resources and verbs are required", s)
+	}
+	for _, verb := range permission.Verbs {
+		if !slices.Contains(knownVerbs, verb) {
+			return permission, fmt.Errorf("invalid permission %q: unknown verb %s, expected one of: %s", s, verb, strings.Join(knownVerbs, ", "))
+		}
+	}
+	return permission, nil
+}
+
+// parsePermissionFields parses the fields of a permission without checking
+// that the required fields are set. List values are separated by ";".
+func parsePermissionFields(s string) (v1alpha1.Permission, error) {
+	permission := v1alpha1.Permission{}
+	for _, field := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return permission, fmt.Errorf("invalid permission %q: expected KEY=VALUE pairs separated by commas", s)
+		}
+		values := strings.Split(value, ";")
+		switch key {
+		case "apiGroups":
+			permission.APIGroups = values
+		case "resources":
+			permission.Resources = values
+		case "verbs":
+			permission.Verbs = values
+		case "resourceNames":
+			permission.ResourceNames = values
+		case "resourceNamespace":
+			permission.ResourceNamespace = value
+		default:
+			return permission, fmt.Errorf("invalid permission %q: unknown key %s, expected one of: apiGroups, resources, verbs, resourceNames, resourceNamespace", s, key)
+		}
+	}
+	return permission, nil
+}
+
+// permissionMatches returns true when every field set on filter is equal to
+// the same field on permission.
+func permissionMatches(permission, filter v1alpha1.Permission) bool {
+	if filter.APIGroups != nil && !slices.Equal(permission.APIGroups, filter.APIGroups) {
+		return false
+	}
+	if filter.Resources != nil && !slices.Equal(permission.Resources, filter.Resources) {
+		return false
+	}
+	if filter.Verbs != nil && !slices.Equal(permission.Verbs, filter.Verbs) {
+		return false
+	}
+	if filter.ResourceNames != nil && !slices.Equal(permission.ResourceNames, filter.ResourceNames) {
+		return false
+	}
+	if filter.ResourceNamespace != "" && permission.ResourceNamespace != filter.ResourceNamespace {
+		return false
+	}
+	return true
+}
+
+func formatPermission(p v1alpha1.Permission) string {
+	fields := []string{
+		"apiGroups=" + strings.Join(p.APIGroups, ";"),
+		"resources=" + strings.Join(p.Resources, ";"),
+		"verbs=" + strings.Join(p.Verbs, ";"),
+	}
+	if len(p.ResourceNames) > 0 {
+		fields = append(fields, "resourceNames="+strings.Join(p.ResourceNames, ";"))
+	}
+	if p.ResourceNamespace != "" {
+		fields = append(fields, "resourceNamespace="+p.ResourceNamespace)
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	kindRegex    = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	versionRegex = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+)
+
+// validatePromiseName checks that name can be used as the name of a Promise,
+// which must be a DNS-1123 subdomain.
+func validatePromiseName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid promise name %q: %s%s", name, strings.Join(errs, "; "), suggestLowercase(name, validation.IsDNS1123Subdomain))
+	}
+	return nil
+}
+
+// validateGroup checks that group can be used as an API group: a DNS-1123
+// subdomain with at least one dot, e.g. example.com.
+func validateGroup(group string) error {
+	if errs := validation.IsDNS1123Subdomain(group); len(errs) > 0 {
+		return fmt.Errorf("invalid group %q: %s%s", group, strings.Join(errs, "; "), suggestLowercase(group, validation.IsDNS1123Subdomain))
+	}
+	if !strings.Contains(group, ".") {
+		return fmt.Errorf("invalid group %q: must contain at least one dot, e.g. %s.example.com", group, group)
+	}
+	return nil
+}
+
+// validateKind checks that kind is PascalCase, e.g. Database or RedisCluster.
+func validateKind(kind string) error {
+	if !kindRegex.MatchString(kind) {
+		suggestion := ""
+		if kind != "" && kindRegex.MatchString(toPascalCase(kind)) {
+			suggestion = fmt.Sprintf(" (did you mean %q?)", toPascalCase(kind))
+		}
+		return fmt.Errorf("invalid kind %q: must be PascalCase, start with an uppercase letter and contain only letters and digits%s", kind, suggestion)
+	}
+	return nil
+}
+
+// validatePlural checks that plural can be used as the plural name of a CRD,
+// which must be a lowercase DNS-1035 label.
+func validatePlural(plural string) error {
+	if errs := validation.IsDNS1035Label(plural); len(errs) > 0 {
+		return fmt.Errorf("invalid plural %q: %s%s", plural, strings.Join(errs, "; "), suggestLowercase(plural, validation.IsDNS1035Label))
+	}
+	return nil
+}
+
+// validateVersion checks that version is a Kubernetes API version, e.g. v1,
+// v1alpha1 or v2beta3.
+func validateVersion(version string) error {
+	if !versionRegex.MatchString(version) {
+		return fmt.Errorf("invalid version %q: must be vN, vNalphaM or vNbetaM where N and M are positive numbers, e.g. v1alpha1", version)
+	}
+	return nil
+}
+
+// validateNamespace checks that namespace can be used as the name of a
+// Kubernetes namespace, which must be a DNS-1123 label.
+func validateNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s%s", namespace, strings.Join(errs, "; "), suggestLowercase(namespace, validation.IsDNS1123Label))
+	}
+	return nil
+}
+
+// validateSecretName checks that name can be used as the name of a
+// Kubernetes Secret, which must be a DNS-1123 subdomain.
+func validateSecretName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid secret name %q: %s%s", name, strings.Join(errs, "; "), suggestLowercase(name, validation.IsDNS1123Subdomain))
+	}
+	return nil
+}
+
+// validateAPIValues validates the API values that are set, so that every
+// problem with the flags is reported before any file is written.
+func validateAPIValues(group, kind, version, plural string) error {
+	var errs []string
+	for _, check := range []struct {
+		value    string
+		validate func(string) error
+	}{
+		{group, validateGroup},
+		{kind, validateKind},
+		{version, validateVersion},
+		{plural, validatePlural},
+	} {
+		if check.value == "" {
+			continue
+		}
+		if err := check.validate(check.value); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func suggestLowercase(value string, validate func(string) []string) string {
+	lower := strings.ToLower(value)
+	if lower != value && len(validate(lower)) == 0 {
+		return fmt.Sprintf(" (did you mean %q?)", lower)
+	}
+	return ""
+}
+
+func toPascalCase(value string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range value {
+		if r == '-' || r == '_' || r == ' ' || r == '.' {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var (
+	irregularPlurals = map[string]string{
+		"person": "people", "child": "children", "man": "men", "woman": "women",
+		"mouse": "mice", "goose": "geese", "foot": "feet", "tooth": "teeth", "ox": "oxen",
+		"index": "indices", "matrix": "matrices", "vertex": "vertices", "criterion": "criteria",
+		"leaf": "leaves", "wolf": "wolves", "knife": "knives", "life": "lives", "half": "halves",
+		"shelf": "shelves", "thief": "thieves", "wife": "wives", "calf": "calves", "loaf": "loaves",
+		"potato": "potatoes", "tomato": "tomatoes", "hero": "heroes", "echo": "echoes",
+		"quiz": "quizzes", "analysis": "analyses", "basis": "bases", "crisis": "crises",
+		"hypothesis": "hypotheses", "thesis": "theses",
+	}
+	uncountableWords = []string{
+		"sheep", "fish", "deer", "series", "species", "information",
+		"equipment", "news", "software", "hardware", "feedback",
+	}
+	// pluralWords are already plural, unlike singular words that end in "s"
+	// such as postgres, lens, address or status
+	pluralWords = []string{
+		"metrics", "settings", "credentials", "permissions", "details",
+		"statistics", "analytics", "secrets", "options", "params",
+	}
+)
+
+// pluralize returns the lowercase plural of a PascalCase kind. Only the last
+// word of the kind is pluralised, so RedisCluster becomes redisclusters and
+// SalesPerson becomes salespeople. It errors when the plural would be the
+// same as the singular, as for Series, AppSettings or RDS, which need
+// --plural.
+func pluralize(kind string) (string, error) {
+	lastWordStart := 0
+	runes := []rune(kind)
+	for i := 1; i < len(runes); i++ {
+		// a word starts at a capital after a lowercase letter or digit, as in
+		// S3Bucket, or at the last capital of an acronym, as in HTTPRoute
+		if unicode.IsUpper(runes[i]) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			lastWordStart = i
+		}
+	}
+	prefix := strings.ToLower(string(runes[:lastWordStart]))
+	lastWord := string(runes[lastWordStart:])
+	word := strings.ToLower(lastWord)
+
+	plural := prefix + pluralizeWord(word, len(lastWord) > 1 && strings.ToUpper(lastWord) == lastWord)
+	if plural == strings.ToLower(kind) {
+		return "", fmt.Errorf("cannot pluralize kind %q: its plural would be the same as its singular %q; set the plural with --plural", kind, plural)
+	}
+	return plural, nil
+}
+
+func pluralizeWord(word string, acronym bool) string {
+	switch {
+	case acronym && strings.HasSuffix(word, "s"):
+		// RDS or DNS cannot be told apart from a plural
+		return word
+	case acronym:
+		return word + "s"
+	}
+
+	if plural, ok := irregularPlurals[word]; ok {
+		return plural
+	}
+	if slices.Contains(uncountableWords, word) || slices.Contains(pluralWords, word) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
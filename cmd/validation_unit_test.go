@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPluralize(t *testing.T) {
+	for kind, want := range map[string]string{
+		"Database":      "databases",
+		"Policy":        "policies",
+		"Gateway":       "gateways",
+		"Address":       "addresses",
+		"Box":           "boxes",
+		"Match":         "matches",
+		"Person":        "people",
+		"SalesPerson":   "salespeople",
+		"Index":         "indices",
+		"RedisCluster":  "redisclusters",
+		"S3Bucket":      "s3buckets",
+		"VPC":           "vpcs",
+		"HTTPRoute":     "httproutes",
+		"RDSInstance":   "rdsinstances",
+		"Proxy":         "proxies",
+		"ClusterConfig": "clusterconfigs",
+		"Metadata":      "metadatas",
+		"Traffic":       "traffics",
+		"Status":        "statuses",
+		"Alias":         "aliases",
+		"Postgres":      "postgreses",
+		"Lens":          "lenses",
+	} {
+		got, err := pluralize(kind)
+		if err != nil {
+			t.Errorf("pluralize(%q) error = %v", kind, err)
+		} else if got != want {
+			t.Errorf("pluralize(%q) = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+func TestPluralizeSameAsSingular(t *testing.T) {
+	for _, kind := range []string{"Series", "Metrics", "AppSettings", "RDS"} {
+		if _, err := pluralize(kind); err == nil || !strings.Contains(err.Error(), "--plural") {
+			t.Errorf("pluralize(%q) error = %v, want an error asking for --plural", kind, err)
+		}
+	}
+}
+
+func TestValidateAPIValues(t *testing.T) {
+	tests := []struct {
+		name                         string
+		group, kind, version, plural string
+		wantErr                      string
+	}{
+		{name: "valid values", group: "syntasso.io", kind: "Database", version: "v1alpha1", plural: "databases"},
+		{name: "unset values are skipped"},
+		{name: "group without a dot", group: "syntasso", wantErr: `invalid group "syntasso": must contain at least one dot`},
+		{name: "uppercase group", group: "Syntasso.io", wantErr: `(did you mean "syntasso.io"?)`},
+		{name: "lowercase kind", kind: "database", wantErr: `invalid kind "database": must be PascalCase`},
+		{name: "kebab-case kind", kind: "redis-cluster", wantErr: `(did you mean "RedisCluster"?)`},
+		{name: "version without v", version: "1", wantErr: `invalid version "1"`},
+		{name: "version with zero", version: "v1alpha0", wantErr: `invalid version "v1alpha0"`},
+		{name: "stable version", version: "v2"},
+		{name: "beta version", version: "v2beta3"},
+		{name: "uppercase plural", plural: "Databases", wantErr: `(did you mean "databases"?)`},
+		{name: "all errors are reported", group: "syntasso", kind: "database", wantErr: "invalid group \"syntasso\": must contain at least one dot, e.g. syntasso.example.com\ninvalid kind"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAPIValues(tt.group, tt.kind, tt.version, tt.plural)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateAPIValues() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateAPIValues() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePromiseName(t *testing.T) {
+	for name, wantErr := range map[string]bool{
+		"postgresql":   false,
+		"my-db.v2":     false,
+		"PostgreSQL":   true,
+		"my_db":        true,
+		"":             true,
+		"-starts-dash": true,
+	} {
+		if err := validatePromiseName(name); (err != nil) != wantErr {
+			t.Errorf("validatePromiseName(%q) error = %v, wantErr %t", name, err, wantErr)
+		}
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for namespace, wantErr := range map[string]bool{
+		"default":     false,
+		"web-system":  false,
+		"Web":         true,
+		"web.system":  true,
+		"-web-system": true,
+	} {
+		if err := validateNamespace(namespace); (err != nil) != wantErr {
+			t.Errorf("validateNamespace(%q) error = %v, wantErr %t", namespace, err, wantErr)
+		}
+	}
+}
+
+func TestValidateSecretName(t *testing.T) {
+	for name, wantErr := range map[string]bool{
+		"chart-credentials":    false,
+		"registry.example.com": false,
+		"Chart_Credentials":    true,
+		"-chart-credentials":   true,
+	} {
+		if err := validateSecretName(name); (err != nil) != wantErr {
+			t.Errorf("validateSecretName(%q) error = %v, wantErr %t", name, err, wantErr)
+		}
+	}
+}
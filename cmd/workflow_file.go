@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	pipelineutils "github.com/syntasso/kratix-cli/cmd/pipeline_utils"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// workflowFile holds the pipelines of a single lifecycle/action workflow and
+// knows how to write them back to the file they were read from: either
+// workflows/LIFECYCLE/ACTION/workflow.yaml for Promises generated with
+// --split, or promise.yaml.
+type workflowFile struct {
+	lifecycle string
+	action    string
+	path      string
+	split     bool
+	promise   v1alpha1.Promise
+	pipelines []v1alpha1.Pipeline
+}
+
+func validatePipelineCmdArgs(c *pipelineutils.PipelineCmdArgs) error {
+	if c.Lifecycle != "promise" && c.Lifecycle != "resource" {
+		return fmt.Errorf("invalid lifecycle: %s, expected one of: promise, resource", c.Lifecycle)
+	}
+
+	if c.Action != "configure" && c.Action != "delete" {
+		return fmt.Errorf("invalid action: %s, expected one of: configure, delete", c.Action)
+	}
+
+	if c.Pipeline == "" {
+		return fmt.Errorf("pipeline name cannot be empty")
+	}
+	return nil
+}
+
+func loadWorkflowFile(promiseDir string, c *pipelineutils.PipelineCmdArgs) (*workflowFile, error) {
+	w := &workflowFile{
+		lifecycle: c.Lifecycle,
+		action:    c.Action,
+		split:     filesGeneratedWithSplit(promiseDir),
+	}
+
+	if w.split {
+		w.path = filepath.Join(promiseDir, "workflows", c.Lifecycle, c.Action, "workflow.yaml")
+		if !workflowFileFound(w.path) {
+			return w, nil
+		}
+		fileBytes, err := promiseFiles.readFile(w.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(fileBytes, &w.pipelines); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", w.path, err)
+		}
+		return w, nil
+	}
+
+	w.path = filepath.Join(promiseDir, promiseFileName)
+	fileBytes, err := promiseFiles.readFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(fileBytes, &w.promise); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", w.path, err)
+	}
+
+	allPipelines, err := v1alpha1.NewPipelinesMap(&w.promise, ctrl.LoggerFrom(context.Background()))
+	if err != nil {
+		return nil, err
+	}
+	w.pipelines, _, err = findPipelinesForLifecycleAction(c, allPipelines)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// pipelineIdx returns the index of the named pipeline, or an error if the
+// workflow does not contain it.
+func (w *workflowFile) pipelineIdx(name string) (int, error) {
+	idx, _ := getPipelineIdx(w.pipelines, name)
+	if idx == -1 {
+		return -1, fmt.Errorf("pipeline %s not found in the %s/%s workflow", name, w.lifecycle, w.action)
+	}
+	return idx, nil
+}
+
+// containerIdx returns the index of the named container in the named
+// pipeline, or an error if either cannot be found.
+func (w *workflowFile) containerIdx(pipelineName, containerName string) (int, int, error) {
+	pipelineIdx, err := w.pipelineIdx(pipelineName)
+	if err != nil {
+		return -1, -1, err
+	}
+	containerIdx := getContainerIdx(w.pipelines[pipelineIdx], containerName)
+	if containerIdx == -1 {
+		return -1, -1, fmt.Errorf("container %s not found in pipeline %s", containerName, pipelineName)
+	}
+	return pipelineIdx, containerIdx, nil
+}
+
+func (w *workflowFile) marshal() ([]byte, error) {
+	pipelinesUnstructured, err := pipelineutils.PipelinesToUnstructured(w.pipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.split {
+		if pipelinesUnstructured == nil {
+			pipelinesUnstructured = []unstructured.Unstructured{}
+		}
+		return yaml.Marshal(pipelinesUnstructured)
+	}
+
+	updatePipeline(w.lifecycle, w.action, pipelinesUnstructured, &w.promise)
+	return yaml.Marshal(w.promise)
+}
+
+func (w *workflowFile) write() error {
+	fileBytes, err := w.marshal()
+	if err != nil {
+		return err
+	}
+	return promiseFiles.writeFile(w.path, fileBytes)
+}
+
+// workflowDir returns the directory holding the files generated for a
+// pipeline, or for one of its containers when containerName is set.
+func workflowDir(promiseDir, lifecycle, action, pipeline, containerName string) string {
+	return filepath.Join(promiseDir, "workflows", lifecycle, action, pipeline, containerName)
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// HelmAuthOptions are the credentials and TLS settings to fetch charts from
+// private chart repositories and OCI registries with.
+type HelmAuthOptions struct {
+	Username              string
+	Password              string
+	CAFile                string
+	CertFile              string
+	KeyFile               string
+	InsecureSkipTLSVerify bool
+	// RegistryConfig is the path of a registry config file, such as the
+	// one written by `helm registry login`. Defaults to Helm's.
+	RegistryConfig string
+}
+
+func (o HelmAuthOptions) tlsEnabled() bool {
+	return o.CAFile != "" || o.CertFile != "" || o.KeyFile != "" || o.InsecureSkipTLSVerify
+}
+
+// NewHelmInstall returns an install action that locates charts with the
+// auth options, both from chart repositories and OCI registries.
+func NewHelmInstall(o HelmAuthOptions) (*action.Install, error) {
+	registryClient, err := newHelmRegistryClient(o)
+	if err != nil {
+		return nil, err
+	}
+
+	install := action.NewInstall(&action.Configuration{RegistryClient: registryClient})
+	install.Username = o.Username
+	install.Password = o.Password
+	install.CaFile = o.CAFile
+	install.CertFile = o.CertFile
+	install.KeyFile = o.KeyFile
+	install.InsecureSkipTLSverify = o.InsecureSkipTLSVerify
+	return install, nil
+}
+
+func newHelmRegistryClient(o HelmAuthOptions) (*registry.Client, error) {
+	options := []registry.ClientOption{
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptCredentialsFile(o.RegistryConfig),
+	}
+	if o.Username != "" || o.Password != "" {
+		options = append(options, registry.ClientOptBasicAuth(o.Username, o.Password))
+	}
+	if o.tlsEnabled() {
+		tlsConfig, err := o.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, registry.ClientOptHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		}))
+	}
+
+	registryClient, err := registry.NewClient(options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	return registryClient, nil
+}
+
+func (o HelmAuthOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: o.InsecureSkipTLSVerify}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if o.CAFile != "" {
+		ca, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
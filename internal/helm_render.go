@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// HelmRenderOptions configures how RenderHelmChart renders a chart.
+type HelmRenderOptions struct {
+	ReleaseName string
+	Namespace   string
+	IncludeCRDs bool
+}
+
+// clusterScopedKinds are the built-in kinds of resources that are not
+// namespaced.
+var clusterScopedKinds = []string{
+	"APIService",
+	"CSIDriver",
+	"CSINode",
+	"CertificateSigningRequest",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"FlowSchema",
+	"IngressClass",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PriorityClass",
+	"PriorityLevelConfiguration",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingAdmissionPolicy",
+	"ValidatingAdmissionPolicyBinding",
+	"ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// IsClusterScopedKind reports whether resources of the kind are cluster
+// scoped. Only built-in kinds are known.
+func IsClusterScopedKind(kind string) bool {
+	return slices.Contains(clusterScopedKinds, kind)
+}
+
+// RenderHelmChart renders the chart like `helm template`, and returns its
+// resources followed by its hooks. Test hooks are left out.
+func RenderHelmChart(helmChart *chart.Chart, values map[string]any, options HelmRenderOptions) ([]string, error) {
+	install := action.NewInstall(&action.Configuration{Log: log.Printf})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.ReleaseName = options.ReleaseName
+	install.Namespace = options.Namespace
+	install.IncludeCRDs = options.IncludeCRDs
+
+	if helmChart.Metadata.Type != "" && helmChart.Metadata.Type != "application" {
+		return nil, fmt.Errorf("chart %s is a %s chart and cannot be rendered", helmChart.Name(), helmChart.Metadata.Type)
+	}
+	if err := action.CheckDependencies(helmChart, helmChart.Metadata.Dependencies); err != nil {
+		return nil, fmt.Errorf("chart %s is missing dependencies: %w", helmChart.Name(), err)
+	}
+
+	rel, err := install.Run(helmChart, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", helmChart.Name(), err)
+	}
+
+	manifests := releaseutil.SplitManifests(rel.Manifest)
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var resources []string
+	for _, key := range keys {
+		resources = append(resources, strings.TrimSpace(manifests[key])+"\n")
+	}
+	for _, hook := range rel.Hooks {
+		if slices.Contains(hook.Events, release.HookTest) {
+			continue
+		}
+		resources = append(resources, fmt.Sprintf("# Source: %s\n%s\n", hook.Path, strings.TrimSpace(hook.Manifest)))
+	}
+	return resources, nil
+}
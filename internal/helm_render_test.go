@@ -0,0 +1,43 @@
+package internal_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+var _ = Describe("RenderHelmChart()", func() {
+	It("renders the chart with the given values and release", func() {
+		helmChart, err := loader.Load("../test/assets/helm/chart-with-crds")
+		Expect(err).NotTo(HaveOccurred())
+
+		resources, err := internal.RenderHelmChart(helmChart, map[string]any{"replicaCount": 3}, internal.HelmRenderOptions{
+			ReleaseName: "widgets",
+			Namespace:   "default",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0]).To(ContainSubstring(`replicas: "3"`))
+		Expect(resources[1]).To(ContainSubstring("kind: ClusterRole"))
+		Expect(resources[1]).To(ContainSubstring("name: widgets-widgets"))
+	})
+
+	It("includes the chart's CRDs when asked to", func() {
+		helmChart, err := loader.Load("../test/assets/helm/chart-with-crds")
+		Expect(err).NotTo(HaveOccurred())
+
+		resources, err := internal.RenderHelmChart(helmChart, nil, internal.HelmRenderOptions{ReleaseName: "widgets", IncludeCRDs: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(3))
+		Expect(resources[0]).To(ContainSubstring("kind: CustomResourceDefinition"))
+	})
+})
+
+var _ = Describe("IsClusterScopedKind()", func() {
+	It("knows the built-in cluster scoped kinds", func() {
+		Expect(internal.IsClusterScopedKind("ClusterRole")).To(BeTrue())
+		Expect(internal.IsClusterScopedKind("CustomResourceDefinition")).To(BeTrue())
+		Expect(internal.IsClusterScopedKind("Deployment")).To(BeFalse())
+	})
+})
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var (
+	// ## @param image.registry [default: REGISTRY_NAME] Image registry
+	helmParamAnnotation = regexp.MustCompile(`^#+\s*@param\s+(\S+)\s*(?:\[[^\]]*\]\s*)?(.*)$`)
+	// commented out values, such as `# cpu: 100m` or `# - name: foo`
+	commentedOutValue = regexp.MustCompile(`^\s*(- )?[a-z][A-Za-z0-9_.\-/]*:(\s.*)?$`)
+)
+
+// AddHelmValuesDocs documents the schema generated for a Helm chart with
+// its values.yaml. Comments above or next to each value become the
+// description of its property, unless it has one, and values that match the
+// type of their property become its default. `## @param PATH DESCRIPTION`
+// annotations, as used by Bitnami charts, take precedence over the other
+// comments.
+func AddHelmValuesDocs(schema *apiextensionsv1.JSONSchemaProps, valuesYAML []byte) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(valuesYAML, &document); err != nil {
+		return fmt.Errorf("failed to parse values.yaml: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	params := map[string]string{}
+	collectHelmParams(&document, params)
+	addValuesDocs(schema, document.Content[0], "", params)
+	return nil
+}
+
+func addValuesDocs(schema *apiextensionsv1.JSONSchemaProps, node *yaml.Node, path string, params map[string]string) {
+	if node.Kind != yaml.MappingNode || schema.Properties == nil {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		property, ok := schema.Properties[key.Value]
+		if !ok {
+			continue
+		}
+		propertyPath := key.Value
+		if path != "" {
+			propertyPath = path + "." + key.Value
+		}
+
+		if property.Description == "" {
+			property.Description = valueDescription(key, value, params[propertyPath])
+		}
+		if property.Default == nil {
+			property.Default = valueDefault(&property, value)
+		}
+		addValuesDocs(&property, value, propertyPath, params)
+		schema.Properties[key.Value] = property
+	}
+}
+
+func collectHelmParams(node *yaml.Node, params map[string]string) {
+	for _, comment := range []string{node.HeadComment, node.LineComment, node.FootComment} {
+		for _, line := range strings.Split(comment, "\n") {
+			if match := helmParamAnnotation.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				params[match[1]] = strings.TrimSpace(match[2])
+			}
+		}
+	}
+	for _, child := range node.Content {
+		collectHelmParams(child, params)
+	}
+}
+
+// valueDescription returns the @param annotation of a value, or else the
+// last paragraph of the comment above it, or else its line comment.
+func valueDescription(key, value *yaml.Node, param string) string {
+	if param != "" {
+		return param
+	}
+	paragraphs := strings.Split(key.HeadComment, "\n\n")
+	if description := commentText(paragraphs[len(paragraphs)-1]); description != "" {
+		return description
+	}
+	if description := commentText(key.LineComment); description != "" {
+		return description
+	}
+	return commentText(value.LineComment)
+}
+
+// commentText returns the text of a comment, without the annotations and
+// commented out values it contains.
+func commentText(comment string) string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "#")
+		line = strings.TrimPrefix(line, " ")
+		if commentedOutValue.MatchString(line) {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		// helm-docs descriptions start with `-- `
+		line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if line == "" || strings.HasPrefix(line, "@") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+// valueDefault returns the value as the default of its property, when it is
+// a scalar or a list of the property's type.
+func valueDefault(property *apiextensionsv1.JSONSchemaProps, node *yaml.Node) *apiextensionsv1.JSON {
+	if node.Kind != yaml.ScalarNode && node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var value any
+	if err := node.Decode(&value); err != nil || value == nil || !matchesType(property, value) {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	for _, allowed := range property.Enum {
+		if string(allowed.Raw) == string(raw) {
+			return &apiextensionsv1.JSON{Raw: raw}
+		}
+	}
+	if len(property.Enum) > 0 {
+		return nil
+	}
+	return &apiextensionsv1.JSON{Raw: raw}
+}
+
+func matchesType(property *apiextensionsv1.JSONSchemaProps, value any) bool {
+	switch value.(type) {
+	case string:
+		return property.Type == "string" || property.XIntOrString
+	case int:
+		return property.Type == "integer" || property.Type == "number" || property.XIntOrString
+	case float64:
+		return property.Type == "number"
+	case bool:
+		return property.Type == "boolean"
+	case []any:
+		return property.Type == "array"
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package internal_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("AddHelmValuesDocs()", func() {
+	schemaFor := func(valuesYAML string) *apiextensionsv1.JSONSchemaProps {
+		var values map[string]any
+		ExpectWithOffset(1, yaml.Unmarshal([]byte(valuesYAML), &values)).To(Succeed())
+		schema, err := internal.HelmValuesToSchema(values)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, internal.AddHelmValuesDocs(schema, []byte(valuesYAML))).To(Succeed())
+		return schema
+	}
+
+	It("turns comments into descriptions and values into defaults", func() {
+		schema := schemaFor(`# This is the first paragraph of the file
+
+# Number of replicas
+# to run
+replicaCount: 2
+
+image:
+  # -- Image repository
+  repository: nginx
+  tag: "1.27" # Overrides the image tag
+  pullPolicy: IfNotPresent
+
+# Resources of the pod
+resources: {}
+  # limits:
+  #   cpu: 100m
+
+enabled: true
+tolerations: []
+nameOverride: null
+`)
+		Expect(schema.Properties["replicaCount"].Description).To(Equal("Number of replicas to run"))
+		Expect(string(schema.Properties["replicaCount"].Default.Raw)).To(Equal("2"))
+
+		image := schema.Properties["image"]
+		Expect(image.Default).To(BeNil())
+		Expect(image.Properties["repository"].Description).To(Equal("Image repository"))
+		Expect(string(image.Properties["repository"].Default.Raw)).To(Equal(`"nginx"`))
+		Expect(image.Properties["tag"].Description).To(Equal("Overrides the image tag"))
+		Expect(string(image.Properties["tag"].Default.Raw)).To(Equal(`"1.27"`))
+		Expect(image.Properties["pullPolicy"].Description).To(BeEmpty())
+
+		Expect(schema.Properties["resources"].Description).To(Equal("Resources of the pod"))
+		Expect(string(schema.Properties["enabled"].Default.Raw)).To(Equal("true"))
+		Expect(string(schema.Properties["tolerations"].Default.Raw)).To(Equal("[]"))
+		Expect(schema.Properties["nameOverride"].Default).To(BeNil())
+	})
+
+	It("uses Bitnami @param annotations", func() {
+		schema := schemaFor(`## @section Common parameters
+
+## @param replicaCount Number of replicas
+## @param image.registry [default: REGISTRY_NAME] Image registry
+## @param image.pullSecrets [array] Image pull secrets
+##
+replicaCount: 1
+image:
+  ## The registry to pull from
+  registry: docker.io
+  pullSecrets: []
+`)
+		Expect(schema.Properties["replicaCount"].Description).To(Equal("Number of replicas"))
+		Expect(schema.Properties["image"].Properties["registry"].Description).To(Equal("Image registry"))
+		Expect(schema.Properties["image"].Properties["pullSecrets"].Description).To(Equal("Image pull secrets"))
+	})
+
+	It("keeps existing descriptions and only sets defaults matching the property type", func() {
+		schema, err := internal.HelmValuesJSONSchemaToSchema([]byte(`{
+			"properties": {
+				"replicaCount": {"type": "integer", "description": "From the schema"},
+				"port": {"type": "string"},
+				"pullPolicy": {"type": "string", "enum": ["Always"]}
+			}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(internal.AddHelmValuesDocs(schema, []byte("# From the comment\nreplicaCount: 1\nport: 80\npullPolicy: IfNotPresent\n"))).To(Succeed())
+
+		Expect(schema.Properties["replicaCount"].Description).To(Equal("From the schema"))
+		Expect(string(schema.Properties["replicaCount"].Default.Raw)).To(Equal("1"))
+		Expect(schema.Properties["port"].Default).To(BeNil())
+		Expect(schema.Properties["pullPolicy"].Default).To(BeNil())
+	})
+})
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// FilterHelmValuesSchema keeps only the properties of the schema matching
+// the include patterns, if any, and drops those matching the exclude
+// patterns. Patterns are dotted paths to values, such as `image.tag`, where
+// each segment is a glob: `persistence.*` matches every value under
+// `persistence`. Matching a property keeps or drops all of its children, and
+// free-form objects are kept whole when a pattern matches values in them.
+// The patterns that match no value are returned.
+func FilterHelmValuesSchema(schema *apiextensionsv1.JSONSchemaProps, include, exclude []string) ([]string, error) {
+	patterns := append(append([]string{}, include...), exclude...)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("invalid values pattern %q", pattern)
+		}
+	}
+	matched := map[string]bool{}
+	filterProperties(schema, nil, include, exclude, len(include) == 0, matched)
+
+	var unmatched []string
+	for _, pattern := range patterns {
+		if !matched[pattern] && !slices.Contains(unmatched, pattern) {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched, nil
+}
+
+func filterProperties(schema *apiextensionsv1.JSONSchemaProps, parent []string, include, exclude []string, included bool, matched map[string]bool) {
+	for name, property := range schema.Properties {
+		propertyPath := append(append([]string{}, parent...), name)
+		if excluded := matchingValuesPatterns(propertyPath, exclude, false); len(excluded) > 0 {
+			markMatched(matched, excluded)
+			removeProperty(schema, name)
+			continue
+		}
+
+		includedBy := matchingValuesPatterns(propertyPath, include, false)
+		markMatched(matched, includedBy)
+		leadingTo := matchingValuesPatterns(propertyPath, include, true)
+		switch {
+		case included || len(includedBy) > 0:
+			filterProperties(&property, propertyPath, nil, exclude, true, matched)
+		case len(leadingTo) > 0 && isFreeFormObject(property):
+			// the values the patterns match cannot be told apart from the
+			// rest of the object, so it is kept whole
+			markMatched(matched, leadingTo)
+		case len(leadingTo) > 0:
+			filterProperties(&property, propertyPath, include, exclude, false, matched)
+			if len(property.Properties) == 0 {
+				removeProperty(schema, name)
+				continue
+			}
+		default:
+			removeProperty(schema, name)
+			continue
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// isFreeFormObject reports whether the property is an object whose keys are
+// not known in advance, such as the `resources: {}` of most charts.
+func isFreeFormObject(property apiextensionsv1.JSONSchemaProps) bool {
+	if len(property.Properties) > 0 {
+		return false
+	}
+	return property.XPreserveUnknownFields != nil && *property.XPreserveUnknownFields || property.AdditionalProperties != nil
+}
+
+func markMatched(matched map[string]bool, patterns []string) {
+	for _, pattern := range patterns {
+		matched[pattern] = true
+	}
+}
+
+// matchingValuesPatterns returns the patterns the path matches or, with
+// prefix, the patterns matching values the path leads to.
+func matchingValuesPatterns(valuePath []string, patterns []string, prefix bool) []string {
+	var matching []string
+	for _, pattern := range patterns {
+		segments := strings.Split(pattern, ".")
+		if prefix && len(segments) <= len(valuePath) || !prefix && len(segments) != len(valuePath) {
+			continue
+		}
+		matched := true
+		for i, segment := range valuePath {
+			if ok, _ := path.Match(segments[i], segment); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matching = append(matching, pattern)
+		}
+	}
+	return matching
+}
+
+// RemoveHelmValues removes the properties set by the given values from the
+// schema, along with the objects left without properties.
+func RemoveHelmValues(schema *apiextensionsv1.JSONSchemaProps, values map[string]any) {
+	for name, value := range values {
+		property, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+		nested, isMap := value.(map[string]any)
+		if !isMap || len(property.Properties) == 0 {
+			removeProperty(schema, name)
+			continue
+		}
+		RemoveHelmValues(&property, nested)
+		if len(property.Properties) == 0 {
+			removeProperty(schema, name)
+			continue
+		}
+		schema.Properties[name] = property
+	}
+}
+
+func removeProperty(schema *apiextensionsv1.JSONSchemaProps, name string) {
+	delete(schema.Properties, name)
+	var required []string
+	for _, r := range schema.Required {
+		if r != name {
+			required = append(required, r)
+		}
+	}
+	schema.Required = required
+}
@@ -0,0 +1,86 @@
+package internal_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Filtering Helm values", func() {
+	var schema *apiextensionsv1.JSONSchemaProps
+
+	BeforeEach(func() {
+		var err error
+		schema, err = internal.HelmValuesToSchema(map[string]any{
+			"replicaCount": 1,
+			"image":        map[string]any{"repository": "nginx", "tag": "1.27"},
+			"persistence":  map[string]any{"enabled": true, "size": "8Gi", "storageClass": map[string]any{"name": "gp2"}},
+			"ingress":      map[string]any{"enabled": false},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		schema.Required = []string{"replicaCount", "image"}
+	})
+
+	Describe("FilterHelmValuesSchema()", func() {
+		It("keeps only the included values", func() {
+			Expect(internal.FilterHelmValuesSchema(schema, []string{"image.tag", "persistence.*"}, nil)).To(BeEmpty())
+
+			Expect(schema.Properties).To(SatisfyAll(HaveLen(2), HaveKey("image"), HaveKey("persistence")))
+			Expect(schema.Properties["image"].Properties).To(SatisfyAll(HaveLen(1), HaveKey("tag")))
+			Expect(schema.Properties["persistence"].Properties["storageClass"].Properties).To(HaveKey("name"))
+			Expect(schema.Required).To(ConsistOf("image"))
+		})
+
+		It("drops the excluded values", func() {
+			Expect(internal.FilterHelmValuesSchema(schema, nil, []string{"ingress", "persistence.storage*"})).To(BeEmpty())
+
+			Expect(schema.Properties).To(SatisfyAll(HaveLen(3), Not(HaveKey("ingress"))))
+			Expect(schema.Properties["persistence"].Properties).To(SatisfyAll(HaveKey("size"), Not(HaveKey("storageClass"))))
+		})
+
+		It("applies the excludes to the included values", func() {
+			Expect(internal.FilterHelmValuesSchema(schema, []string{"persistence"}, []string{"persistence.size"})).To(BeEmpty())
+
+			Expect(schema.Properties).To(HaveLen(1))
+			Expect(schema.Properties["persistence"].Properties).To(SatisfyAll(HaveKey("enabled"), Not(HaveKey("size"))))
+		})
+
+		It("fails on invalid patterns", func() {
+			_, err := internal.FilterHelmValuesSchema(schema, []string{"image.[tag"}, nil)
+			Expect(err).To(MatchError(`invalid values pattern "image.[tag"`))
+		})
+
+		It("keeps free-form objects that the included values are in", func() {
+			schema.Properties["resources"] = apiextensionsv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: pointer.Bool(true)}
+
+			Expect(internal.FilterHelmValuesSchema(schema, []string{"resources.*", "image.tag"}, nil)).To(BeEmpty())
+
+			Expect(schema.Properties).To(SatisfyAll(HaveLen(2), HaveKey("resources"), HaveKey("image")))
+			Expect(*schema.Properties["resources"].XPreserveUnknownFields).To(BeTrue())
+		})
+
+		It("returns the patterns that match no value", func() {
+			Expect(internal.FilterHelmValuesSchema(schema, []string{"image.tag", "imag.repository", "missing.*"}, []string{"image.digest"})).To(
+				Equal([]string{"imag.repository", "missing.*", "image.digest"}))
+
+			Expect(schema.Properties).To(SatisfyAll(HaveLen(1), HaveKey("image")))
+		})
+	})
+
+	Describe("RemoveHelmValues()", func() {
+		It("removes the given values and the objects left empty", func() {
+			internal.RemoveHelmValues(schema, map[string]any{
+				"replicaCount": 2,
+				"image":        map[string]any{"repository": "my-registry/nginx", "tag": "1.28"},
+				"persistence":  map[string]any{"storageClass": map[string]any{"name": "gp3"}},
+				"unknown":      true,
+			})
+
+			Expect(schema.Properties).To(SatisfyAll(HaveLen(2), HaveKey("persistence"), HaveKey("ingress")))
+			Expect(schema.Properties["persistence"].Properties).To(SatisfyAll(HaveLen(2), Not(HaveKey("storageClass"))))
+			Expect(schema.Required).To(BeEmpty())
+		})
+	})
+})
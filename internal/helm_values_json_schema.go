@@ -0,0 +1,425 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+// HelmValuesJSONSchemaToSchema converts the values.schema.json of a Helm
+// chart, a JSON Schema (draft-07), into a structural OpenAPI v3 schema that
+// can be used in a CRD.
+//
+// Local $refs are resolved. oneOf, anyOf and allOf are merged into a single
+// schema: their properties are all kept, and branches of different types
+// accept any value. Keywords a CRD does not support are dropped.
+func HelmValuesJSONSchemaToSchema(valuesSchema []byte) (*apiextensionsv1.JSONSchemaProps, error) {
+	var root map[string]any
+	if err := json.Unmarshal(valuesSchema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse values.schema.json: %w", err)
+	}
+
+	c := &jsonSchemaConverter{root: root, resolving: map[string]bool{}}
+	schema, err := c.convert(root)
+	if err != nil {
+		return nil, err
+	}
+	schema.Type = "object"
+	schema.Nullable = false
+	if schema.Properties == nil && schema.XPreserveUnknownFields == nil {
+		schema.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+	}
+	schema.Default = &apiextensionsv1.JSON{Raw: []byte(`{}`)}
+	return schema, nil
+}
+
+// RemoveDefaultedRequired drops the required properties that the chart's
+// values already set. Helm validates values.schema.json against the chart's
+// values merged with the user's, but the CRD only sees the request, so those
+// properties must not be required from every request.
+func RemoveDefaultedRequired(schema *apiextensionsv1.JSONSchemaProps, values map[string]any) {
+	var required []string
+	for _, name := range schema.Required {
+		if value, ok := values[name]; !ok || value == nil {
+			required = append(required, name)
+		}
+	}
+	schema.Required = required
+
+	for name, property := range schema.Properties {
+		nested, ok := values[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		RemoveDefaultedRequired(&property, nested)
+		schema.Properties[name] = property
+	}
+}
+
+type jsonSchemaConverter struct {
+	root map[string]any
+	// resolving holds the $refs being converted, to stop at recursive ones
+	resolving map[string]bool
+}
+
+func (c *jsonSchemaConverter) convert(node any) (*apiextensionsv1.JSONSchemaProps, error) {
+	switch n := node.(type) {
+	case bool:
+		// `true` accepts anything; `false` accepts nothing, which a CRD cannot
+		// express
+		return anyValueSchema(), nil
+	case map[string]any:
+		schema, err := c.build(n)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := n["$ref"]; !ok {
+			// the schema of a $ref is already final
+			finalize(schema, n)
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("invalid schema %v in values.schema.json", node)
+	}
+}
+
+// build converts the keywords of a schema, without making it structural
+// yet, so that it can be merged with others.
+func (c *jsonSchemaConverter) build(node map[string]any) (*apiextensionsv1.JSONSchemaProps, error) {
+	if ref, ok := node["$ref"].(string); ok {
+		return c.buildRef(ref, node)
+	}
+
+	schema := &apiextensionsv1.JSONSchemaProps{}
+	if err := c.setType(schema, node["type"]); err != nil {
+		return nil, err
+	}
+	setValidations(schema, node)
+
+	if properties, ok := node["properties"].(map[string]any); ok {
+		schema.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+		for name, property := range properties {
+			propertySchema, err := c.convert(property)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			schema.Properties[name] = *propertySchema
+		}
+	}
+	if err := c.setAdditionalProperties(schema, node); err != nil {
+		return nil, err
+	}
+	if err := c.setItems(schema, node["items"]); err != nil {
+		return nil, err
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, ok := node[keyword].([]any)
+		if !ok {
+			continue
+		}
+		for _, branch := range branches {
+			branchSchema := anyValueSchema()
+			if branchNode, ok := branch.(map[string]any); ok {
+				var err error
+				if branchSchema, err = c.build(branchNode); err != nil {
+					return nil, err
+				}
+			}
+			mergeSchemas(schema, branchSchema)
+		}
+	}
+	return schema, nil
+}
+
+// finalize makes a schema structural: every field has a type, or accepts
+// any value, and every array has items.
+func finalize(schema *apiextensionsv1.JSONSchemaProps, node map[string]any) {
+	inferType(schema)
+	// JSON Schema allows fields that are not listed unless additionalProperties
+	// is false, where a CRD drops them
+	if schema.Type == "object" && schema.AdditionalProperties == nil && node["additionalProperties"] != false {
+		schema.XPreserveUnknownFields = pointer.Bool(true)
+	}
+	if schema.Type == "array" && schema.Items == nil {
+		schema.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: anyValueSchema()}
+	}
+}
+
+// buildRef converts the schema a local $ref points to. Keywords next to the
+// $ref, such as a description, are kept.
+func (c *jsonSchemaConverter) buildRef(ref string, node map[string]any) (*apiextensionsv1.JSONSchemaProps, error) {
+	if !strings.HasPrefix(ref, "#") || c.resolving[ref] {
+		// remote and recursive $refs are not followed
+		return anyValueSchema(), nil
+	}
+	target, err := c.resolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	c.resolving[ref] = true
+	schema, err := c.convert(target)
+	delete(c.resolving, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	siblings := map[string]any{}
+	for key, value := range node {
+		if key != "$ref" {
+			siblings[key] = value
+		}
+	}
+	if len(siblings) > 0 {
+		siblingSchema, err := c.build(siblings)
+		if err != nil {
+			return nil, err
+		}
+		mergeSchemas(schema, siblingSchema)
+		if siblingSchema.Description != "" {
+			schema.Description = siblingSchema.Description
+		}
+	}
+	return schema, nil
+}
+
+// resolveRef returns the part of the schema a JSON pointer such as
+// #/definitions/image points to.
+func (c *jsonSchemaConverter) resolveRef(ref string) (any, error) {
+	var target any = c.root
+	pointer := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	if pointer == "" {
+		return target, nil
+	}
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := target.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("failed to resolve $ref %q in values.schema.json", ref)
+		}
+		if target, ok = object[token]; !ok {
+			return nil, fmt.Errorf("failed to resolve $ref %q in values.schema.json", ref)
+		}
+	}
+	return target, nil
+}
+
+// setType sets the type of the schema. Nullable types are marked nullable;
+// values that can be either strings or integers are int-or-string; other
+// combinations of types accept any value.
+func (c *jsonSchemaConverter) setType(schema *apiextensionsv1.JSONSchemaProps, jsonType any) error {
+	var types []string
+	switch t := jsonType.(type) {
+	case nil:
+		return nil
+	case string:
+		types = []string{t}
+	case []any:
+		for _, value := range t {
+			if s, ok := value.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid type %v in values.schema.json", jsonType)
+	}
+
+	var nonNullTypes []string
+	for _, t := range types {
+		if t == "null" {
+			schema.Nullable = true
+			continue
+		}
+		nonNullTypes = append(nonNullTypes, t)
+	}
+	sort.Strings(nonNullTypes)
+
+	switch {
+	case len(nonNullTypes) == 1:
+		schema.Type = nonNullTypes[0]
+	case strings.Join(nonNullTypes, ",") == "integer,string":
+		schema.XIntOrString = true
+	case len(nonNullTypes) > 1:
+		schema.XPreserveUnknownFields = pointer.Bool(true)
+	}
+	return nil
+}
+
+func (c *jsonSchemaConverter) setAdditionalProperties(schema *apiextensionsv1.JSONSchemaProps, node map[string]any) error {
+	additionalProperties, ok := node["additionalProperties"].(map[string]any)
+	if !ok || len(schema.Properties) > 0 {
+		// a CRD cannot have both properties and additionalProperties; unknown
+		// fields are kept instead
+		return nil
+	}
+	valueSchema, err := c.convert(additionalProperties)
+	if err != nil {
+		return err
+	}
+	schema.AdditionalProperties = &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: valueSchema}
+	return nil
+}
+
+func (c *jsonSchemaConverter) setItems(schema *apiextensionsv1.JSONSchemaProps, items any) error {
+	switch i := items.(type) {
+	case nil:
+	case []any:
+		// tuples cannot be expressed in a CRD
+		schema.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: anyValueSchema()}
+	default:
+		itemSchema, err := c.convert(i)
+		if err != nil {
+			return err
+		}
+		schema.Items = &apiextensionsv1.JSONSchemaPropsOrArray{Schema: itemSchema}
+	}
+	return nil
+}
+
+// setValidations copies the keywords that mean the same in a CRD
+func setValidations(schema *apiextensionsv1.JSONSchemaProps, node map[string]any) {
+	schema.Description, _ = node["description"].(string)
+	schema.Title, _ = node["title"].(string)
+	schema.Pattern, _ = node["pattern"].(string)
+	schema.Format, _ = node["format"].(string)
+
+	if value, ok := node["default"]; ok && value != nil {
+		schema.Default = jsonValue(value)
+	}
+	if values, ok := node["enum"].([]any); ok {
+		for _, value := range values {
+			schema.Enum = append(schema.Enum, *jsonValue(value))
+		}
+	}
+	if value, ok := node["const"]; ok {
+		schema.Enum = []apiextensionsv1.JSON{*jsonValue(value)}
+	}
+
+	schema.Minimum = float64Value(node["minimum"])
+	schema.Maximum = float64Value(node["maximum"])
+	// draft-07 exclusive bounds are numbers, OpenAPI v3 ones are booleans
+	if exclusiveMinimum := float64Value(node["exclusiveMinimum"]); exclusiveMinimum != nil {
+		schema.Minimum, schema.ExclusiveMinimum = exclusiveMinimum, true
+	}
+	if exclusiveMaximum := float64Value(node["exclusiveMaximum"]); exclusiveMaximum != nil {
+		schema.Maximum, schema.ExclusiveMaximum = exclusiveMaximum, true
+	}
+	schema.MultipleOf = float64Value(node["multipleOf"])
+	schema.MinLength = int64Value(node["minLength"])
+	schema.MaxLength = int64Value(node["maxLength"])
+	schema.MinItems = int64Value(node["minItems"])
+	schema.MaxItems = int64Value(node["maxItems"])
+	schema.MinProperties = int64Value(node["minProperties"])
+	schema.MaxProperties = int64Value(node["maxProperties"])
+
+	if required, ok := node["required"].([]any); ok {
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				schema.Required = append(schema.Required, s)
+			}
+		}
+	}
+}
+
+// mergeSchemas adds the properties and validations of other to schema.
+// Schemas of different types are merged into one that accepts any value.
+func mergeSchemas(schema, other *apiextensionsv1.JSONSchemaProps) {
+	switch {
+	case schema.Type == "":
+		schema.Type = other.Type
+	case other.Type == "" || other.Type == schema.Type:
+	case isOneOf(schema.Type, other.Type, "integer", "number"):
+		schema.Type = "number"
+	case isOneOf(schema.Type, other.Type, "integer", "string"):
+		schema.Type, schema.XIntOrString = "", true
+	default:
+		*schema = apiextensionsv1.JSONSchemaProps{Description: schema.Description, XPreserveUnknownFields: pointer.Bool(true)}
+		return
+	}
+
+	if len(other.Properties) > 0 && schema.Properties == nil {
+		schema.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+	}
+	for name, property := range other.Properties {
+		if existing, ok := schema.Properties[name]; ok {
+			mergeSchemas(&existing, &property)
+			property = existing
+		}
+		schema.Properties[name] = property
+	}
+	if schema.Items == nil {
+		schema.Items = other.Items
+	}
+	if schema.AdditionalProperties == nil {
+		schema.AdditionalProperties = other.AdditionalProperties
+	}
+	if other.XPreserveUnknownFields != nil {
+		schema.XPreserveUnknownFields = other.XPreserveUnknownFields
+	}
+	if len(schema.Properties) > 0 && schema.AdditionalProperties != nil {
+		schema.AdditionalProperties, schema.XPreserveUnknownFields = nil, pointer.Bool(true)
+	}
+	schema.Nullable = schema.Nullable || other.Nullable
+	schema.XIntOrString = schema.XIntOrString || other.XIntOrString
+	if schema.Description == "" {
+		schema.Description = other.Description
+	}
+	if schema.Default == nil {
+		schema.Default = other.Default
+	}
+}
+
+// isOneOf returns true when the types a and b are t1 and t2, in any order
+func isOneOf(a, b, t1, t2 string) bool {
+	return (a == t1 && b == t2) || (a == t2 && b == t1)
+}
+
+// inferType sets the type of schemas without one from their keywords, as
+// every field of a structural schema needs one.
+func inferType(schema *apiextensionsv1.JSONSchemaProps) {
+	if schema.Type != "" || schema.XIntOrString {
+		return
+	}
+	switch {
+	case schema.Properties != nil || schema.AdditionalProperties != nil:
+		schema.Type = "object"
+	case schema.Items != nil:
+		schema.Type = "array"
+	case schema.Pattern != "" || schema.MinLength != nil || schema.MaxLength != nil:
+		schema.Type = "string"
+	case schema.Minimum != nil || schema.Maximum != nil:
+		schema.Type = "number"
+	default:
+		schema.XPreserveUnknownFields = pointer.Bool(true)
+	}
+}
+
+// anyValueSchema accepts any value, of any type
+func anyValueSchema() *apiextensionsv1.JSONSchemaProps {
+	return &apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: pointer.Bool(true)}
+}
+
+func jsonValue(value any) *apiextensionsv1.JSON {
+	raw, _ := json.Marshal(value)
+	return &apiextensionsv1.JSON{Raw: raw}
+}
+
+func float64Value(value any) *float64 {
+	if f, ok := value.(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+func int64Value(value any) *int64 {
+	if f, ok := value.(float64); ok {
+		i := int64(f)
+		return &i
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package internal_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("HelmValuesJSONSchemaToSchema()", func() {
+	convert := func(valuesSchema string) *apiextensionsv1.JSONSchemaProps {
+		schema, err := internal.HelmValuesJSONSchemaToSchema([]byte(valuesSchema))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, schema.Type).To(Equal("object"))
+		ExpectWithOffset(1, string(schema.Default.Raw)).To(Equal("{}"))
+		return schema
+	}
+
+	It("keeps types, descriptions, defaults and validations", func() {
+		schema := convert(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"required": ["replicaCount"],
+			"properties": {
+				"replicaCount": {"type": "integer", "description": "Number of replicas", "default": 1, "minimum": 1, "exclusiveMaximum": 10},
+				"name": {"type": "string", "pattern": "^[a-z]+$", "maxLength": 63},
+				"pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent"]},
+				"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+			}
+		}`)
+		Expect(schema.Required).To(ConsistOf("replicaCount"))
+
+		replicas := schema.Properties["replicaCount"]
+		Expect(replicas.Type).To(Equal("integer"))
+		Expect(replicas.Description).To(Equal("Number of replicas"))
+		Expect(string(replicas.Default.Raw)).To(Equal("1"))
+		Expect(*replicas.Minimum).To(Equal(1.0))
+		Expect(*replicas.Maximum).To(Equal(10.0))
+		Expect(replicas.ExclusiveMaximum).To(BeTrue())
+
+		Expect(schema.Properties["name"].Pattern).To(Equal("^[a-z]+$"))
+		Expect(*schema.Properties["name"].MaxLength).To(Equal(int64(63)))
+		Expect(schema.Properties["pullPolicy"].Enum).To(HaveLen(2))
+		Expect(schema.Properties["tags"].Items.Schema.Type).To(Equal("string"))
+		Expect(schema.Properties["tags"].UniqueItems).To(BeFalse())
+	})
+
+	It("resolves $refs", func() {
+		schema := convert(`{
+			"definitions": {
+				"image": {
+					"type": "object",
+					"properties": {"repository": {"type": "string"}, "tag": {"type": "string"}},
+					"additionalProperties": false
+				}
+			},
+			"properties": {
+				"image": {"$ref": "#/definitions/image", "description": "The image to run"},
+				"sidecar": {"properties": {"image": {"$ref": "#/definitions/image"}}}
+			}
+		}`)
+		image := schema.Properties["image"]
+		Expect(image.Type).To(Equal("object"))
+		Expect(image.Description).To(Equal("The image to run"))
+		Expect(image.Properties).To(HaveKey("repository"))
+		Expect(image.XPreserveUnknownFields).To(BeNil())
+
+		Expect(schema.Properties["sidecar"].Type).To(Equal("object"))
+		Expect(schema.Properties["sidecar"].Properties["image"].Properties["tag"].Type).To(Equal("string"))
+	})
+
+	It("stops at recursive $refs", func() {
+		schema := convert(`{
+			"definitions": {"node": {"type": "object", "properties": {"child": {"$ref": "#/definitions/node"}}}},
+			"properties": {"tree": {"$ref": "#/definitions/node"}}
+		}`)
+		child := schema.Properties["tree"].Properties["child"]
+		Expect(*child.XPreserveUnknownFields).To(BeTrue())
+		Expect(child.Type).To(BeEmpty())
+	})
+
+	It("fails on $refs that do not exist", func() {
+		_, err := internal.HelmValuesJSONSchemaToSchema([]byte(`{"properties": {"image": {"$ref": "#/definitions/image"}}}`))
+		Expect(err).To(MatchError(ContainSubstring(`failed to resolve $ref "#/definitions/image"`)))
+	})
+
+	It("merges oneOf branches", func() {
+		schema := convert(`{
+			"properties": {
+				"port": {"oneOf": [{"type": "integer"}, {"type": "string"}]},
+				"storage": {
+					"type": "object",
+					"oneOf": [
+						{"properties": {"size": {"type": "string"}}, "required": ["size"]},
+						{"properties": {"existingClaim": {"type": "string"}}, "required": ["existingClaim"]}
+					]
+				},
+				"anything": {"oneOf": [{"type": "boolean"}, {"type": "object"}]}
+			}
+		}`)
+		Expect(schema.Properties["port"].XIntOrString).To(BeTrue())
+		Expect(schema.Properties["port"].Type).To(BeEmpty())
+
+		storage := schema.Properties["storage"]
+		Expect(storage.Type).To(Equal("object"))
+		Expect(storage.Properties).To(SatisfyAll(HaveKey("size"), HaveKey("existingClaim")))
+		Expect(storage.Required).To(BeEmpty())
+
+		Expect(*schema.Properties["anything"].XPreserveUnknownFields).To(BeTrue())
+		Expect(schema.Properties["anything"].Type).To(BeEmpty())
+	})
+
+	It("makes the schema structural", func() {
+		schema := convert(`{
+			"properties": {
+				"annotations": {"type": "object"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"nameOverride": {"type": ["string", "null"]},
+				"extraEnv": {"type": "array"},
+				"untyped": {}
+			}
+		}`)
+		Expect(*schema.Properties["annotations"].XPreserveUnknownFields).To(BeTrue())
+		Expect(schema.Properties["labels"].AdditionalProperties.Schema.Type).To(Equal("string"))
+		Expect(schema.Properties["nameOverride"].Type).To(Equal("string"))
+		Expect(schema.Properties["nameOverride"].Nullable).To(BeTrue())
+		Expect(*schema.Properties["extraEnv"].Items.Schema.XPreserveUnknownFields).To(BeTrue())
+		Expect(*schema.Properties["untyped"].XPreserveUnknownFields).To(BeTrue())
+	})
+
+	Describe("RemoveDefaultedRequired()", func() {
+		It("only keeps the required properties the chart's values do not set", func() {
+			schema := convert(`{
+				"required": ["image", "replicaCount", "password"],
+				"properties": {
+					"image": {"type": "object", "required": ["repository", "digest"], "properties": {"repository": {"type": "string"}, "digest": {"type": "string"}}},
+					"replicaCount": {"type": "integer"},
+					"password": {"type": "string"}
+				}
+			}`)
+
+			internal.RemoveDefaultedRequired(schema, map[string]any{
+				"image":        map[string]any{"repository": "nginx"},
+				"replicaCount": 1,
+				"password":     nil,
+			})
+
+			Expect(schema.Required).To(ConsistOf("password"))
+			Expect(schema.Properties["image"].Required).To(ConsistOf("digest"))
+		})
+	})
+
+	It("fails on invalid JSON", func() {
+		_, err := internal.HelmValuesJSONSchemaToSchema([]byte(`{`))
+		Expect(err).To(MatchError(ContainSubstring("failed to parse values.schema.json")))
+	})
+})
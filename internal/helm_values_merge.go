@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// HelmValuesChange is a value of a Helm chart that was added, removed or
+// changed between two versions of the chart.
+type HelmValuesChange struct {
+	Path   string
+	Change string
+}
+
+const (
+	HelmValueAdded   = "added"
+	HelmValueRemoved = "removed"
+	HelmValueChanged = "changed"
+)
+
+// DiffHelmValuesSchemas returns the values added, removed and changed
+// between the schemas of two versions of a chart, sorted by path. The
+// children of added and removed values are not listed.
+func DiffHelmValuesSchemas(oldSchema, newSchema *apiextensionsv1.JSONSchemaProps) []HelmValuesChange {
+	var changes []HelmValuesChange
+	diffProperties(oldSchema, newSchema, "", &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffProperties(oldSchema, newSchema *apiextensionsv1.JSONSchemaProps, parent string, changes *[]HelmValuesChange) {
+	for name, newProperty := range newSchema.Properties {
+		path := joinValuesPath(parent, name)
+		oldProperty, ok := oldSchema.Properties[name]
+		if !ok {
+			*changes = append(*changes, HelmValuesChange{Path: path, Change: HelmValueAdded})
+			continue
+		}
+		if !sameProperty(oldProperty, newProperty) {
+			*changes = append(*changes, HelmValuesChange{Path: path, Change: HelmValueChanged})
+		}
+		diffProperties(&oldProperty, &newProperty, path, changes)
+	}
+	for name := range oldSchema.Properties {
+		if _, ok := newSchema.Properties[name]; !ok {
+			*changes = append(*changes, HelmValuesChange{Path: joinValuesPath(parent, name), Change: HelmValueRemoved})
+		}
+	}
+}
+
+// MergeHelmValuesSchemas applies the changes between the schemas of two
+// versions of a chart to the current schema of a Promise API. Properties
+// added to the API by hand are kept, as are the changes made by hand to
+// properties the chart did not change. Without an old schema, values
+// removed from the chart cannot be told apart from properties added by hand,
+// and are kept.
+func MergeHelmValuesSchemas(current, oldSchema, newSchema *apiextensionsv1.JSONSchemaProps) {
+	if oldSchema == nil {
+		oldSchema = &apiextensionsv1.JSONSchemaProps{}
+		mergeProperties(current, oldSchema, newSchema, false)
+		return
+	}
+	mergeProperties(current, oldSchema, newSchema, true)
+}
+
+func mergeProperties(current, oldSchema, newSchema *apiextensionsv1.JSONSchemaProps, removeValues bool) {
+	if current.Properties == nil && len(newSchema.Properties) > 0 {
+		current.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+	}
+
+	for name, newProperty := range newSchema.Properties {
+		currentProperty, inCurrent := current.Properties[name]
+		oldProperty, inOld := oldSchema.Properties[name]
+		switch {
+		case !inCurrent && inOld && removeValues:
+			// removed from the API by hand
+			continue
+		case !inCurrent:
+			current.Properties[name] = newProperty
+			continue
+		case inOld && !sameProperty(oldProperty, newProperty):
+			// the children added by hand are kept, unless the value is no
+			// longer an object
+			children := currentProperty.Properties
+			currentProperty = newProperty
+			currentProperty.Properties = nil
+			if newProperty.Type == "object" {
+				currentProperty.Properties = children
+			}
+		}
+		mergeProperties(&currentProperty, &oldProperty, &newProperty, removeValues)
+		current.Properties[name] = currentProperty
+	}
+
+	if removeValues {
+		for name := range oldSchema.Properties {
+			if _, ok := newSchema.Properties[name]; !ok {
+				delete(current.Properties, name)
+			}
+		}
+	}
+
+	var required []string
+	for _, name := range current.Required {
+		if slices.Contains(oldSchema.Required, name) && !slices.Contains(newSchema.Required, name) {
+			continue
+		}
+		required = append(required, name)
+	}
+	for _, name := range newSchema.Required {
+		if !slices.Contains(oldSchema.Required, name) && !slices.Contains(required, name) {
+			required = append(required, name)
+		}
+	}
+	current.Required = slices.DeleteFunc(required, func(name string) bool {
+		_, ok := current.Properties[name]
+		return !ok
+	})
+	if len(current.Required) == 0 {
+		current.Required = nil
+	}
+}
+
+// sameProperty reports whether two properties are the same, without
+// comparing their children.
+func sameProperty(a, b apiextensionsv1.JSONSchemaProps) bool {
+	a.Properties, b.Properties = nil, nil
+	a.Required, b.Required = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+func joinValuesPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,100 @@
+package internal_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("Merging Helm values", func() {
+	var oldSchema, newSchema *apiextensionsv1.JSONSchemaProps
+
+	BeforeEach(func() {
+		var err error
+		oldSchema, err = internal.HelmValuesToSchema(map[string]any{
+			"replicaCount": 1,
+			"image":        map[string]any{"repository": "nginx", "tag": "1.27"},
+			"ingress":      map[string]any{"enabled": false},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		oldSchema.Required = []string{"replicaCount"}
+
+		newSchema, err = internal.HelmValuesToSchema(map[string]any{
+			"replicaCount": "1",
+			"image":        map[string]any{"repository": "nginx", "tag": "1.28", "pullPolicy": "Always"},
+			"resources":    map[string]any{"limits": map[string]any{"cpu": "1"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		newSchema.Required = []string{"image"}
+	})
+
+	Describe("DiffHelmValuesSchemas()", func() {
+		It("lists the values added, removed and changed, sorted by path", func() {
+			Expect(internal.DiffHelmValuesSchemas(oldSchema, newSchema)).To(Equal([]internal.HelmValuesChange{
+				{Path: "image.pullPolicy", Change: internal.HelmValueAdded},
+				{Path: "ingress", Change: internal.HelmValueRemoved},
+				{Path: "replicaCount", Change: internal.HelmValueChanged},
+				{Path: "resources", Change: internal.HelmValueAdded},
+			}))
+		})
+
+		It("lists nothing for the same schemas", func() {
+			Expect(internal.DiffHelmValuesSchemas(oldSchema, oldSchema)).To(BeEmpty())
+		})
+	})
+
+	Describe("MergeHelmValuesSchemas()", func() {
+		var current *apiextensionsv1.JSONSchemaProps
+
+		BeforeEach(func() {
+			current = oldSchema.DeepCopy()
+			current.Properties["region"] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+			image := current.Properties["image"]
+			image.Properties["tag"] = apiextensionsv1.JSONSchemaProps{Type: "string", Description: "Pinned by the platform team"}
+			current.Properties["image"] = image
+			current.Required = append(current.Required, "region")
+		})
+
+		It("applies the chart's changes and keeps the changes made by hand", func() {
+			internal.MergeHelmValuesSchemas(current, oldSchema, newSchema)
+
+			Expect(current.Properties).To(SatisfyAll(
+				HaveLen(4),
+				HaveKey("region"),
+				HaveKey("resources"),
+				Not(HaveKey("ingress")),
+			))
+			Expect(current.Properties["replicaCount"].Type).To(Equal("string"))
+			Expect(current.Properties["image"].Properties).To(HaveKey("pullPolicy"))
+			Expect(current.Properties["image"].Properties["tag"].Description).To(Equal("Pinned by the platform team"))
+			Expect(current.Required).To(ConsistOf("region", "image"))
+		})
+
+		It("drops the children of values that are no longer objects", func() {
+			newSchema.Properties["image"] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+
+			internal.MergeHelmValuesSchemas(current, oldSchema, newSchema)
+
+			Expect(current.Properties["image"].Type).To(Equal("string"))
+			Expect(current.Properties["image"].Properties).To(BeEmpty())
+		})
+
+		It("does not add back the values removed by hand", func() {
+			delete(current.Properties, "replicaCount")
+
+			internal.MergeHelmValuesSchemas(current, oldSchema, newSchema)
+
+			Expect(current.Properties).NotTo(HaveKey("replicaCount"))
+		})
+
+		It("only adds values without the old schema", func() {
+			internal.MergeHelmValuesSchemas(current, nil, newSchema)
+
+			Expect(current.Properties).To(SatisfyAll(HaveKey("ingress"), HaveKey("region"), HaveKey("resources")))
+			Expect(current.Properties["replicaCount"].Type).To(Equal(oldSchema.Properties["replicaCount"].Type))
+			Expect(current.Properties["image"].Properties).To(HaveKey("pullPolicy"))
+			Expect(current.Required).To(ConsistOf("replicaCount", "region", "image"))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// IsGitTemplateSource returns true when source should be cloned with git
+// rather than read from the local filesystem.
+func IsGitTemplateSource(source string) bool {
+	if strings.HasPrefix(source, "git::") || strings.HasPrefix(source, "git@") {
+		return true
+	}
+	for _, scheme := range []string{"https://", "http://", "ssh://", "file://"} {
+		if strings.HasPrefix(source, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchTemplateSource resolves a template source to a local directory. Local
+// paths are returned as-is. Git sources follow the same conventions as
+// Terraform module sources: a "//subdir" suffix selects a directory in the
+// repository and "?ref=" selects a branch, tag or commit. The returned cleanup
+// function removes any temporary clone and is always safe to call.
+func FetchTemplateSource(source string) (string, func(), error) {
+	noop := func() {}
+	if !IsGitTemplateSource(source) {
+		info, err := os.Stat(source)
+		if err != nil {
+			return "", noop, fmt.Errorf("failed to read template directory %s: %w", source, err)
+		}
+		if !info.IsDir() {
+			return "", noop, fmt.Errorf("template %s is not a directory", source)
+		}
+		return source, noop, nil
+	}
+
+	repoURL, subdir, ref, err := parseGitTemplateSource(source)
+	if err != nil {
+		return "", noop, err
+	}
+
+	cloneDir, err := mkdirTemp("", "kratix-template")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(cloneDir) }
+
+	if ref == "" {
+		err = runGitCommand("", "clone", "--depth", "1", repoURL, cloneDir)
+	} else {
+		err = runGitCommand("", "clone", repoURL, cloneDir)
+		if err == nil {
+			err = runGitCommand(cloneDir, "checkout", ref)
+		}
+	}
+	if err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to fetch template from %s: %w", source, err)
+	}
+
+	templateDir := filepath.Join(cloneDir, subdir)
+	if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+		cleanup()
+		return "", noop, fmt.Errorf("template directory %q not found in %s", subdir, repoURL)
+	}
+	return templateDir, cleanup, nil
+}
+
+func parseGitTemplateSource(source string) (repoURL, subdir, ref string, err error) {
+	subdir = extractSubdirFromSource(source)
+
+	s := strings.TrimPrefix(source, "git::")
+	s, query, _ := strings.Cut(s, "?")
+	if query != "" {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return "", "", "", fmt.Errorf("invalid template source %s: %w", source, err)
+		}
+		ref = values.Get("ref")
+	}
+
+	if subdir != "" {
+		s = strings.TrimSuffix(s, "//"+subdir)
+	}
+	return s, subdir, ref, nil
+}
+
+func runGitCommand(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
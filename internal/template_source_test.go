@@ -0,0 +1,76 @@
+package internal_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix-cli/internal"
+)
+
+var _ = Describe("FetchTemplateSource", func() {
+	It("returns local directories as-is", func() {
+		dir := GinkgoT().TempDir()
+		templateDir, cleanup, err := internal.FetchTemplateSource(dir)
+		Expect(err).NotTo(HaveOccurred())
+		defer cleanup()
+		Expect(templateDir).To(Equal(dir))
+	})
+
+	It("errors when the local path is not a directory", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "file")
+		Expect(os.WriteFile(file, []byte("hi"), 0644)).To(Succeed())
+		_, _, err := internal.FetchTemplateSource(file)
+		Expect(err).To(MatchError(ContainSubstring("is not a directory")))
+	})
+
+	When("the source is a git repository", func() {
+		var repoDir string
+
+		git := func(args ...string) {
+			cmd := exec.Command("git", append([]string{"-C", repoDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+		}
+
+		BeforeEach(func() {
+			if _, err := exec.LookPath("git"); err != nil {
+				Skip("git is not installed")
+			}
+			repoDir = GinkgoT().TempDir()
+			git("init", "-q")
+			Expect(os.MkdirAll(filepath.Join(repoDir, "go"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(repoDir, "go", "Dockerfile.tpl"), []byte("v1"), 0644)).To(Succeed())
+			git("add", "-A")
+			git("commit", "-q", "-m", "v1")
+			git("tag", "v1")
+			Expect(os.WriteFile(filepath.Join(repoDir, "go", "Dockerfile.tpl"), []byte("v2"), 0644)).To(Succeed())
+			git("commit", "-q", "-am", "v2")
+		})
+
+		It("clones the repository and selects the subdirectory", func() {
+			templateDir, cleanup, err := internal.FetchTemplateSource("git::file://" + repoDir + "//go")
+			Expect(err).NotTo(HaveOccurred())
+			defer cleanup()
+			Expect(filepath.Join(templateDir, "Dockerfile.tpl")).To(BeARegularFile())
+			Expect(os.ReadFile(filepath.Join(templateDir, "Dockerfile.tpl"))).To(BeEquivalentTo("v2"))
+
+			cleanup()
+			Expect(templateDir).NotTo(BeADirectory())
+		})
+
+		It("checks out the requested ref", func() {
+			templateDir, cleanup, err := internal.FetchTemplateSource("git::file://" + repoDir + "//go?ref=v1")
+			Expect(err).NotTo(HaveOccurred())
+			defer cleanup()
+			Expect(os.ReadFile(filepath.Join(templateDir, "Dockerfile.tpl"))).To(BeEquivalentTo("v1"))
+		})
+
+		It("errors when the subdirectory does not exist", func() {
+			_, _, err := internal.FetchTemplateSource("git::file://" + repoDir + "//python")
+			Expect(err).To(MatchError(ContainSubstring(`template directory "python" not found`)))
+		})
+	})
+})
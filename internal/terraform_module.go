@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -50,9 +51,11 @@ func SetupModule(moduleSource, moduleRegistryVersion string) (string, error) {
 	return resolveModuleDir(tempDir)
 }
 
+// GetVariablesFromModule extracts the variables declared in every .tf file
+// of a Terraform module, in file name order. Variables declared more than
+// once are reported and only their first declaration is kept.
 func GetVariablesFromModule(moduleSource, moduleDir, moduleRegistryVersion string) ([]TerraformVariable, error) {
-	absPath := filepath.Join(moduleDir, "variables.tf")
-	variables, err := extractVariablesFromVarsFile(absPath)
+	variables, err := extractVariablesFromModuleDir(moduleDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse variables: %w", err)
 	}
@@ -60,24 +63,62 @@ func GetVariablesFromModule(moduleSource, moduleDir, moduleRegistryVersion strin
 	return variables, nil
 }
 
-// GetOutputsFromModule extracts the names of the outputs from a Terraform
-// module's outputs.tf. If outputs.tf does not exist or has no outputs, it
-// returns an empty slice.
+// GetOutputsFromModule extracts the names of the outputs declared in every
+// .tf file of a Terraform module. If the module has no outputs, it returns
+// an empty slice.
 func GetOutputsFromModule(moduleDir string) ([]string, error) {
-	outputsPath := filepath.Join(moduleDir, "outputs.tf")
-	if _, err := os.Stat(outputsPath); err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
-		return nil, fmt.Errorf("failed to stat outputs file: %w", err)
-	}
-	outputs, err := extractOutputsFromFile(outputsPath)
+	files, err := moduleFiles(moduleDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse outputs: %w", err)
 	}
+
+	outputs := []string{}
+	declared := map[string]string{}
+	for _, path := range files {
+		blocks, err := parseHCLBlocks(path, "output")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse outputs: %w", err)
+		}
+		for _, block := range blocks {
+			if len(block.Labels) == 0 {
+				continue
+			}
+			name, source := block.Labels[0], blockSource(block)
+			if firstSource, ok := declared[name]; ok {
+				fmt.Fprintf(os.Stderr, "warning: output %s at %s is already declared at %s, skipping\n", name, source, firstSource)
+				continue
+			}
+			declared[name] = source
+			outputs = append(outputs, name)
+		}
+	}
 	return outputs, nil
 }
 
+// moduleFiles returns the .tf files of a module directory, sorted by name.
+// Override files are left out, as they only change blocks declared in the
+// other files.
+func moduleFiles(moduleDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(moduleDir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if name == "override.tf" || strings.HasSuffix(name, "_override.tf") {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 func GetVersionsAndProvidersFromModule(moduleSource, moduleDir, moduleRegistryVersion string, moduleProviderFilenames []string) (filepaths []string, err error) {
 	var versionProviderFilepaths []string
 	for _, filename := range moduleProviderFilenames {
@@ -128,7 +169,7 @@ func retryWithRequiredVarPlaceholders(tempDir, moduleSource, moduleRegistryVersi
 		moduleDir = filepath.Join(downloadedBaseDir, subdir)
 	}
 
-	variables, err := extractVariablesFromVarsFile(filepath.Join(moduleDir, "variables.tf"))
+	variables, err := extractVariablesFromModuleDir(moduleDir)
 	if err != nil {
 		return fmt.Errorf("failed to initialize terraform, could not extract variables: %w", initErr)
 	}
@@ -263,29 +304,32 @@ func IsTerraformRegistrySource(moduleSource string) bool {
 	return strings.Count(moduleSource, "/") >= 2
 }
 
-func extractOutputsFromFile(filePath string) ([]string, error) {
-	parser := hclparse.NewParser()
-	file, diags := parser.ParseHCLFile(filePath)
-	if diags.HasErrors() {
-		return nil, fmt.Errorf("failed to parse HCL file: %s", diags.Error())
+func extractVariablesFromModuleDir(moduleDir string) ([]TerraformVariable, error) {
+	files, err := moduleFiles(moduleDir)
+	if err != nil {
+		return nil, err
 	}
-
-	content, diags := file.Body.Content(&hcl.BodySchema{
-		Blocks: []hcl.BlockHeaderSchema{
-			{Type: "output", LabelNames: []string{"name"}},
-		},
-	})
-	if diags.HasErrors() {
-		return nil, fmt.Errorf("failed to parse body content: %s", diags.Error())
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .tf files found in %s", moduleDir)
 	}
 
-	var outputNames []string
-	for _, block := range content.Blocks {
-		if block.Type == "output" && len(block.Labels) > 0 {
-			outputNames = append(outputNames, block.Labels[0])
+	var variables []TerraformVariable
+	declared := map[string]string{}
+	for _, path := range files {
+		fileVariables, err := extractVariablesFromVarsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range fileVariables {
+			if source, ok := declared[variable.Name]; ok {
+				fmt.Fprintf(os.Stderr, "warning: variable %s at %s is already declared at %s, skipping\n", variable.Name, variable.Source, source)
+				continue
+			}
+			declared[variable.Name] = variable.Source
+			variables = append(variables, variable)
 		}
 	}
-	return outputNames, nil
+	return variables, nil
 }
 
 func extractVariablesFromVarsFile(filePath string) ([]TerraformVariable, error) {
@@ -294,7 +338,7 @@ func extractVariablesFromVarsFile(filePath string) ([]TerraformVariable, error)
 		return nil, err
 	}
 
-	blocks, err := parseHCLVariables(filePath)
+	blocks, err := parseHCLBlocks(filePath, "variable")
 	if err != nil {
 		return nil, err
 	}
@@ -310,16 +354,18 @@ func readFileContent(filePath string) (string, error) {
 	return string(fileBytes), nil
 }
 
-func parseHCLVariables(filePath string) ([]*hcl.Block, error) {
+// parseHCLBlocks returns the blocks of the given type, such as variable or
+// output, ignoring the other blocks of the file.
+func parseHCLBlocks(filePath, blockType string) ([]*hcl.Block, error) {
 	parser := hclparse.NewParser()
 	file, diags := parser.ParseHCLFile(filePath)
 	if diags.HasErrors() {
 		return nil, fmt.Errorf("failed to parse HCL file: %s", diags.Error())
 	}
 
-	content, diags := file.Body.Content(&hcl.BodySchema{
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
 		Blocks: []hcl.BlockHeaderSchema{
-			{Type: "variable", LabelNames: []string{"name"}},
+			{Type: blockType, LabelNames: []string{"name"}},
 		},
 	})
 	if diags.HasErrors() {
@@ -329,6 +375,11 @@ func parseHCLVariables(filePath string) ([]*hcl.Block, error) {
 	return content.Blocks, nil
 }
 
+// blockSource returns the file name and line a block is declared at.
+func blockSource(block *hcl.Block) string {
+	return fmt.Sprintf("%s:%d", filepath.Base(block.DefRange.Filename), block.DefRange.Start.Line)
+}
+
 func extractVariables(blocks []*hcl.Block, fileContent string) []TerraformVariable {
 	var variables []TerraformVariable
 
@@ -336,7 +387,7 @@ func extractVariables(blocks []*hcl.Block, fileContent string) []TerraformVariab
 		if block.Type != "variable" || len(block.Labels) == 0 {
 			continue
 		}
-		variable := TerraformVariable{Name: block.Labels[0]}
+		variable := TerraformVariable{Name: block.Labels[0], Source: blockSource(block)}
 		varContent, _ := block.Body.Content(&hcl.BodySchema{
 			Attributes: []hcl.AttributeSchema{
 				{Name: "type", Required: false},
@@ -349,7 +400,7 @@ func extractVariables(blocks []*hcl.Block, fileContent string) []TerraformVariab
 		variable.Description = extractDescription(varContent, fileContent)
 		d, err := extractDefault(varContent)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error extracting default for variable %s: %s\n", variable.Name, err)
+			fmt.Fprintf(os.Stderr, "Error extracting default for variable %s at %s: %s\n", variable.Name, variable.Source, err)
 			fmt.Fprintln(os.Stderr, "Continuing without default value")
 		}
 		variable.Default = d
@@ -515,6 +515,52 @@ variable "bool_var" {
 				Expect(variables[3].Description).To(BeEmpty())
 			})
 		})
+		Context("when the variables are declared across the module's .tf files", func() {
+			var moduleDir string
+
+			BeforeEach(func() {
+				moduleDir = filepath.Join(tempDir, "module")
+				Expect(os.MkdirAll(moduleDir, 0o755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`
+resource "aws_s3_bucket" "this" {
+  bucket = var.bucket_name
+}
+
+variable "bucket_name" {
+  type = string
+}
+`), 0o644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(moduleDir, "inputs.tf"), []byte(`variable "region" {
+  type    = string
+  default = "eu-west-2"
+}
+
+variable "bucket_name" {
+  type = number
+}
+`), 0o644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(moduleDir, "main_override.tf"), []byte(`variable "overridden" {
+  type = string
+}
+`), 0o644)).To(Succeed())
+			})
+
+			It("returns the variables of every file, keeping the first declaration of duplicates", func() {
+				variables, err := internal.GetVariablesFromModule("mock-source", moduleDir, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(variables).To(Equal([]internal.TerraformVariable{
+					{Name: "region", Type: "string", Default: "eu-west-2", Source: "inputs.tf:1"},
+					{Name: "bucket_name", Type: "number", Source: "inputs.tf:6"},
+				}))
+			})
+		})
+
+		Context("when the module has no .tf files", func() {
+			It("errors", func() {
+				_, err := internal.GetVariablesFromModule("mock-source", tempDir, "")
+				Expect(err).To(MatchError(ContainSubstring("no .tf files found")))
+			})
+		})
 	})
 
 	Describe("#GetVersionsAndProvidersFromModule", func() {
@@ -728,6 +774,33 @@ output "s3_bucket_arn" {
 		})
 	})
 
+	When("outputs are declared across the module's .tf files", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`
+resource "aws_s3_bucket" "this" {}
+
+output "s3_bucket_id" {
+  value = aws_s3_bucket.this.id
+}
+`), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(moduleDir, "outputs.tf"), []byte(`
+output "s3_bucket_arn" {
+  value = aws_s3_bucket.this.arn
+}
+
+output "s3_bucket_id" {
+  value = aws_s3_bucket.this.bucket
+}
+`), 0o644)).To(Succeed())
+		})
+
+		It("returns the output names of every file once", func() {
+			outputs, err := internal.GetOutputsFromModule(moduleDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputs).To(Equal([]string{"s3_bucket_id", "s3_bucket_arn"}))
+		})
+	})
+
 	When("outputs.tf does not exist", func() {
 		It("returns an empty slice", func() {
 			outputs, err := internal.GetOutputsFromModule(moduleDir)
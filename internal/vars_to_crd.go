@@ -14,6 +14,8 @@ type TerraformVariable struct {
 	Type        string
 	Description string
 	Default     any
+	// Source is the file name and line the variable is declared at.
+	Source string
 }
 
 func VariablesToCRDSpecSchema(variables []TerraformVariable) (*v1.JSONSchemaProps, []string) {
@@ -26,10 +28,15 @@ func VariablesToCRDSpecSchema(variables []TerraformVariable) (*v1.JSONSchemaProp
 	var warnings []string
 
 	for _, v := range variables {
+		name := v.Name
+		if v.Source != "" {
+			name = fmt.Sprintf("%s (%s)", v.Name, v.Source)
+		}
+
 		if v.Type == "" {
 			inferredType := inferTypeFromDefault(v.Default)
 			if inferredType == "" {
-				warnings = append(warnings, fmt.Sprintf("warning: Type not set for variable %s and cannot be inferred from the default value, skipping", v.Name))
+				warnings = append(warnings, fmt.Sprintf("warning: Type not set for variable %s and cannot be inferred from the default value, skipping", name))
 				continue
 			}
 			v.Type = inferredType
@@ -37,7 +44,7 @@ func VariablesToCRDSpecSchema(variables []TerraformVariable) (*v1.JSONSchemaProp
 
 		prop, warn := convertTerraformTypeToCRD(v)
 		if warn != "" {
-			warnings = append(warnings, fmt.Sprintf("warning: unable to automatically convert %s of type %s into CRD, skipping", v.Name, v.Type))
+			warnings = append(warnings, fmt.Sprintf("warning: unable to automatically convert %s of type %s into CRD, skipping", name, v.Type))
 			continue
 		}
 
@@ -47,11 +54,11 @@ func VariablesToCRDSpecSchema(variables []TerraformVariable) (*v1.JSONSchemaProp
 
 		if v.Default != nil {
 			if strings.Contains(v.Type, "object") {
-				warnings = append(warnings, fmt.Sprintf("warning: default value for variable %s is set but type %s does not support defaults, skipping", v.Name, v.Type))
+				warnings = append(warnings, fmt.Sprintf("warning: default value for variable %s is set but type %s does not support defaults, skipping", name, v.Type))
 			} else {
 				raw, err := json.Marshal(v.Default)
 				if err != nil {
-					warnings = append(warnings, fmt.Sprintf("warning: failed to marshal default value for variable %s: %v", v.Name, err))
+					warnings = append(warnings, fmt.Sprintf("warning: failed to marshal default value for variable %s: %v", name, err))
 				} else {
 					prop.Default = &v1.JSON{Raw: raw}
 				}
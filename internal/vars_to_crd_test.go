@@ -128,5 +128,16 @@ var _ = Describe("VariablesToCRDSpecSchema", func() {
 				"warning: Type not set for variable defaultMap and cannot be inferred from the default value, skipping",
 			))
 		})
+
+		It("should include where the variables are declared", func() {
+			vars := []internal.TerraformVariable{
+				{Name: "unknownVar", Source: "main.tf:12"},
+			}
+			_, warnings := internal.VariablesToCRDSpecSchema(vars)
+
+			Expect(warnings).To(ConsistOf(
+				"warning: Type not set for variable unknownVar (main.tf:12) and cannot be inferred from the default value, skipping",
+			))
+		})
 	})
 })
@@ -0,0 +1,173 @@
+// The helm-promise stage renders a Helm chart for each request, using the
+// request's spec as the chart's values.
+//
+// It is configured with the following environment variables:
+//
+//	CHART_URL                  URL of the chart: OCI, tarball, local path, or repository URL with CHART_NAME
+//	CHART_NAME                 name of the chart in the repository at CHART_URL
+//	CHART_VERSION              version of the chart, defaults to the latest
+//	TARGET_NAMESPACE           namespace of the release, defaults to "default"; namespaced resources without a
+//	                           namespace are set to it
+//	NAMESPACE_FROM_REQUEST     "true" to release the chart in the namespace of the request, like TARGET_NAMESPACE
+//	RELEASE_NAME_TEMPLATE      Go template rendered with the request to name the release, defaults to {{ .metadata.name }}
+//	FIXED_VALUES               values, in YAML, set for every request and taking precedence over its spec
+//	FIXED_VALUES_FILES         comma separated paths of values files, applied after FIXED_VALUES
+//	INCLUDE_CRDS               "true" to render the CRDs in the chart's crds/ directory
+//	EXCLUDE_CRDS               "true" to leave out the CRDs rendered by the chart's templates
+//	EXCLUDE_CLUSTER_RESOURCES  "true" to leave out every cluster scoped resource
+//	RESOURCE_LABELS            comma separated KEY=VALUE labels added to every rendered resource
+//	SPLIT_OUTPUT               "true" to write each resource to its own file instead of object.yaml
+//	HELM_CREDENTIALS_DIR       directory of the credentials to fetch the chart with: the files username, password,
+//	                           ca.crt, tls.crt, tls.key and .dockerconfigjson, all optional
+//	INSECURE_SKIP_TLS_VERIFY   "true" to skip the TLS certificate checks of the chart repository or OCI registry
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func run() error {
+	inputObjectFilepath := GetEnv("KRATIX_INPUT_FILE", "/kratix/input/object.yaml")
+	outputDir := GetEnv("KRATIX_OUTPUT_DIR", "/kratix/output")
+
+	chartURL := os.Getenv("CHART_URL")
+	if chartURL == "" {
+		return fmt.Errorf("CHART_URL environment variable is not set")
+	}
+
+	inputObject, err := os.ReadFile(inputObjectFilepath)
+	if err != nil {
+		return fmt.Errorf("failed to read request from %s: %w", inputObjectFilepath, err)
+	}
+
+	var request map[string]any
+	if err := yaml.Unmarshal(inputObject, &request); err != nil {
+		return fmt.Errorf("failed to parse request: %w", err)
+	}
+
+	releaseName, err := renderReleaseName(GetEnv("RELEASE_NAME_TEMPLATE", defaultReleaseNameTemplate), request)
+	if err != nil {
+		return err
+	}
+
+	namespace, err := targetNamespace(request)
+	if err != nil {
+		return err
+	}
+
+	values, err := requestValues(request, os.Getenv("FIXED_VALUES"), parseList(os.Getenv("FIXED_VALUES_FILES")))
+	if err != nil {
+		return err
+	}
+
+	labels, err := parseLabels(os.Getenv("RESOURCE_LABELS"))
+	if err != nil {
+		return err
+	}
+
+	auth, err := helmAuthOptions(os.Getenv("HELM_CREDENTIALS_DIR"), os.Getenv("INSECURE_SKIP_TLS_VERIFY") == "true")
+	if err != nil {
+		return err
+	}
+
+	resources, err := renderChart(chartOptions{
+		URL:         chartURL,
+		Name:        os.Getenv("CHART_NAME"),
+		Version:     os.Getenv("CHART_VERSION"),
+		Namespace:   namespace,
+		ReleaseName: releaseName,
+		IncludeCRDs: os.Getenv("INCLUDE_CRDS") == "true",
+		Auth:        auth,
+	}, values)
+	if err != nil {
+		return err
+	}
+
+	resources, err = excludeResources(resources, os.Getenv("EXCLUDE_CRDS") == "true", os.Getenv("EXCLUDE_CLUSTER_RESOURCES") == "true")
+	if err != nil {
+		return err
+	}
+
+	if err := setNamespace(resources, namespace); err != nil {
+		return err
+	}
+
+	if err := addLabels(resources, labels); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	files := map[string][]string{"object.yaml": resources}
+	if os.Getenv("SPLIT_OUTPUT") == "true" {
+		files = splitResources(resources)
+	}
+
+	for name, documents := range files {
+		path := filepath.Join(outputDir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(documents, "---\n")), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	fmt.Printf("Chart %s rendered as release %s to %s\n", chartURL, releaseName, outputDir)
+	return nil
+}
+
+// GetEnv retrieves an environment variable or returns a default value if not set
+func GetEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// parseList splits a comma-separated list, trimming whitespace.
+func parseList(env string) []string {
+	var items []string
+	for s := range strings.SplitSeq(env, ",") {
+		if trimmed := strings.TrimSpace(s); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// targetNamespace returns the namespace to release the chart in.
+func targetNamespace(request map[string]any) (string, error) {
+	if os.Getenv("NAMESPACE_FROM_REQUEST") != "true" {
+		return os.Getenv("TARGET_NAMESPACE"), nil
+	}
+	metadata, _ := request["metadata"].(map[string]any)
+	namespace, _ := metadata["namespace"].(string)
+	if namespace == "" {
+		return "", fmt.Errorf("NAMESPACE_FROM_REQUEST is set but the request has no metadata.namespace")
+	}
+	return namespace, nil
+}
+
+func parseLabels(env string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, label := range parseList(env) {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q in RESOURCE_LABELS, expected KEY=VALUE", label)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
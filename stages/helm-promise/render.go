@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/syntasso/kratix-cli/internal"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultReleaseNameTemplate = "{{ .metadata.name }}"
+
+type chartOptions struct {
+	URL         string
+	Name        string
+	Version     string
+	Namespace   string
+	ReleaseName string
+	IncludeCRDs bool
+	Auth        internal.HelmAuthOptions
+}
+
+// helmAuthOptions returns the credentials found in credentialsDir, where the
+// pipeline mounts the Promise's credentials Secret.
+func helmAuthOptions(credentialsDir string, insecureSkipTLSVerify bool) (internal.HelmAuthOptions, error) {
+	options := internal.HelmAuthOptions{InsecureSkipTLSVerify: insecureSkipTLSVerify}
+	if credentialsDir == "" {
+		return options, nil
+	}
+	if _, err := os.Stat(credentialsDir); err != nil {
+		return options, fmt.Errorf("failed to read HELM_CREDENTIALS_DIR: %w", err)
+	}
+
+	for key, value := range map[string]*string{
+		"ca.crt":            &options.CAFile,
+		"tls.crt":           &options.CertFile,
+		"tls.key":           &options.KeyFile,
+		".dockerconfigjson": &options.RegistryConfig,
+	} {
+		path := filepath.Join(credentialsDir, key)
+		if _, err := os.Stat(path); err == nil {
+			*value = path
+		}
+	}
+	for key, value := range map[string]*string{
+		"username": &options.Username,
+		"password": &options.Password,
+	} {
+		contents, err := os.ReadFile(filepath.Join(credentialsDir, key))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return options, fmt.Errorf("failed to read %s from HELM_CREDENTIALS_DIR: %w", key, err)
+		}
+		*value = strings.TrimSpace(string(contents))
+	}
+	return options, nil
+}
+
+// renderReleaseName renders the release name template with the request.
+func renderReleaseName(releaseNameTemplate string, request map[string]any) (string, error) {
+	t, err := template.New("release-name").Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(releaseNameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid RELEASE_NAME_TEMPLATE %q: %w", releaseNameTemplate, err)
+	}
+	var name bytes.Buffer
+	if err := t.Execute(&name, request); err != nil {
+		return "", fmt.Errorf("failed to render RELEASE_NAME_TEMPLATE %q: %w", releaseNameTemplate, err)
+	}
+	if strings.TrimSpace(name.String()) == "" {
+		return "", fmt.Errorf("RELEASE_NAME_TEMPLATE %q rendered an empty release name", releaseNameTemplate)
+	}
+	return strings.TrimSpace(name.String()), nil
+}
+
+type valuesLayer struct {
+	source   string
+	contents []byte
+}
+
+// requestValues returns the values to render the chart with: the request's
+// spec, overridden by the fixed values and then by each fixed values file.
+func requestValues(request map[string]any, fixedValues string, fixedValuesFiles []string) (map[string]any, error) {
+	values, _ := request["spec"].(map[string]any)
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	layers := []valuesLayer{{"FIXED_VALUES", []byte(fixedValues)}}
+	for _, file := range fixedValuesFiles {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixed values file: %w", err)
+		}
+		layers = append(layers, valuesLayer{file, contents})
+	}
+
+	for _, layer := range layers {
+		var layerValues map[string]any
+		if err := yaml.Unmarshal(layer.contents, &layerValues); err != nil {
+			return nil, fmt.Errorf("failed to parse fixed values from %s: %w", layer.source, err)
+		}
+		values = chartutil.MergeTables(layerValues, values)
+	}
+	return values, nil
+}
+
+// renderChart fetches the chart and renders it like `helm template`.
+func renderChart(options chartOptions, values map[string]any) ([]string, error) {
+	settings := cli.New()
+	install, err := internal.NewHelmInstall(options.Auth)
+	if err != nil {
+		return nil, err
+	}
+	install.Version = options.Version
+	chartRef := options.URL
+	if options.Name != "" {
+		install.RepoURL = options.URL
+		chartRef = options.Name
+	}
+
+	chartPath, err := install.LocateChart(chartRef, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart %s: %w", chartRef, err)
+	}
+
+	helmChart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", chartRef, err)
+	}
+
+	// settings.Namespace() is the pod's own namespace in a pipeline, so the
+	// release defaults to the "default" namespace explicitly
+	namespace := "default"
+	if options.Namespace != "" {
+		namespace = options.Namespace
+	}
+	return internal.RenderHelmChart(helmChart, values, internal.HelmRenderOptions{
+		ReleaseName: options.ReleaseName,
+		Namespace:   namespace,
+		IncludeCRDs: options.IncludeCRDs,
+	})
+}
+
+// excludeResources drops the CRDs, or all the cluster scoped resources, the
+// Promise installs as dependencies instead.
+func excludeResources(resources []string, excludeCRDs, excludeClusterResources bool) ([]string, error) {
+	if !excludeCRDs && !excludeClusterResources {
+		return resources, nil
+	}
+	var kept []string
+	for _, resource := range resources {
+		object, err := parseResource(resource)
+		if err != nil {
+			return nil, err
+		}
+		if object != nil {
+			kind := object.GetKind()
+			if excludeCRDs && kind == "CustomResourceDefinition" || excludeClusterResources && internal.IsClusterScopedKind(kind) {
+				continue
+			}
+		}
+		kept = append(kept, resource)
+	}
+	return kept, nil
+}
+
+// addLabels adds the labels to every rendered resource.
+func addLabels(resources []string, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	for i, resource := range resources {
+		object, err := parseResource(resource)
+		if err != nil {
+			return err
+		}
+		if object == nil {
+			continue
+		}
+		resourceLabels := object.GetLabels()
+		if resourceLabels == nil {
+			resourceLabels = map[string]string{}
+		}
+		for key, value := range labels {
+			resourceLabels[key] = value
+		}
+		object.SetLabels(resourceLabels)
+
+		labelled, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return fmt.Errorf("failed to add labels to %s %s: %w", object.GetKind(), object.GetName(), err)
+		}
+		resources[i] = string(labelled)
+	}
+	return nil
+}
+
+// setNamespace sets the namespace of the namespaced resources that do not
+// set one, which `helm template` leaves to the client applying them.
+// Resources of kinds internal.IsClusterScopedKind does not know, such as
+// cluster scoped custom resources, get a namespace the API server ignores.
+func setNamespace(resources []string, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	for i, resource := range resources {
+		object, err := parseResource(resource)
+		if err != nil {
+			return err
+		}
+		if object == nil || object.GetNamespace() != "" || internal.IsClusterScopedKind(object.GetKind()) {
+			continue
+		}
+		object.SetNamespace(namespace)
+
+		namespaced, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return fmt.Errorf("failed to set the namespace of %s %s: %w", object.GetKind(), object.GetName(), err)
+		}
+		resources[i] = string(namespaced)
+	}
+	return nil
+}
+
+// splitResources names a file after the kind, namespace and name of each
+// resource.
+func splitResources(resources []string) map[string][]string {
+	files := map[string][]string{}
+	for i, resource := range resources {
+		object, err := parseResource(resource)
+		if err != nil {
+			files[fmt.Sprintf("resource-%d.yaml", i)] = []string{resource}
+			continue
+		}
+		if object == nil {
+			continue
+		}
+
+		var parts []string
+		for _, part := range []string{object.GetKind(), object.GetNamespace(), object.GetName()} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		name := strings.ToLower(strings.Join(parts, "_"))
+		fileName := name + ".yaml"
+		for n := 2; files[fileName] != nil; n++ {
+			fileName = fmt.Sprintf("%s_%d.yaml", name, n)
+		}
+		files[fileName] = []string{resource}
+	}
+	return files
+}
+
+// parseResource returns the resource in a rendered manifest, or nil when the
+// manifest is empty.
+func parseResource(manifest string) (*unstructured.Unstructured, error) {
+	var object map[string]any
+	if err := yaml.Unmarshal([]byte(manifest), &object); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered manifest: %w\n%s", err, manifest)
+	}
+	if len(object) == 0 {
+		return nil, nil
+	}
+	return &unstructured.Unstructured{Object: object}, nil
+}
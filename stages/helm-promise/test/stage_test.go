@@ -0,0 +1,279 @@
+package run_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func runWithEnv(envVars map[string]string) *gexec.Session {
+	cmd := exec.Command(binaryPath)
+	for key, value := range envVars {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	EventuallyWithOffset(1, session, "20s").Should(gexec.Exit())
+	return session
+}
+
+func readResources(path string) []unstructured.Unstructured {
+	contents, err := os.ReadFile(path)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	var resources []unstructured.Unstructured
+	for _, document := range strings.Split(string(contents), "\n---\n") {
+		var object map[string]any
+		ExpectWithOffset(1, yaml.Unmarshal([]byte(document), &object)).To(Succeed())
+		if len(object) > 0 {
+			resources = append(resources, unstructured.Unstructured{Object: object})
+		}
+	}
+	return resources
+}
+
+// serveChartRepo serves the chart from a Helm chart repository behind basic
+// auth, and returns the repository URL.
+func serveChartRepo(chartDir, username, password string) string {
+	repoDir, err := os.MkdirTemp("", "kratix-helm-repo")
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	DeferCleanup(os.RemoveAll, repoDir)
+
+	helmChart, err := loader.Load(chartDir)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	_, err = chartutil.Save(helmChart, repoDir)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	fileServer := http.FileServer(http.Dir(repoDir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, _ := req.BasicAuth(); user != username || pass != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fileServer.ServeHTTP(w, req)
+	}))
+	DeferCleanup(server.Close)
+	index, err := repo.IndexDirectory(repoDir, server.URL)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, index.WriteFile(filepath.Join(repoDir, "index.yaml"), 0644)).To(Succeed())
+	return server.URL
+}
+
+func kinds(resources []unstructured.Unstructured) []string {
+	var kinds []string
+	for _, resource := range resources {
+		kinds = append(kinds, resource.GetKind())
+	}
+	return kinds
+}
+
+var _ = Describe("From Helm chart to Promise Stage", func() {
+	var (
+		envVars map[string]string
+		tmpDir  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "kratix")
+		Expect(err).NotTo(HaveOccurred())
+		chartPath, err := filepath.Abs("assets/chart")
+		Expect(err).NotTo(HaveOccurred())
+
+		envVars = map[string]string{
+			"HOME":              tmpDir,
+			"KRATIX_INPUT_FILE": "assets/test-object.yaml",
+			"KRATIX_OUTPUT_DIR": tmpDir,
+			"CHART_URL":         chartPath,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("renders the chart with the request's spec as values", func() {
+		session := runWithEnv(envVars)
+		Expect(session).To(gexec.Exit(0))
+		Expect(session.Out).To(gbytes.Say("rendered as release test-object to %s", tmpDir))
+
+		resources := readResources(filepath.Join(tmpDir, "object.yaml"))
+		Expect(kinds(resources)).To(Equal([]string{"ClusterRole", "Service", "Deployment", "Job"}))
+
+		deployment := resources[2]
+		Expect(deployment.GetName()).To(Equal("test-object"))
+		Expect(deployment.GetNamespace()).To(Equal("default"))
+		Expect(deployment.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeNumerically("==", 3))))
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers[0].(map[string]any)["image"]).To(Equal("nginx:1.28"))
+	})
+
+	It("releases the chart in the default namespace whatever the namespace of the pipeline", func() {
+		envVars["HELM_NAMESPACE"] = "kratix-platform-system"
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		deployment := readResources(filepath.Join(tmpDir, "object.yaml"))[2]
+		Expect(deployment.GetNamespace()).To(Equal("default"))
+	})
+
+	It("sets the fixed values over the request's", func() {
+		fixedValuesPath, err := filepath.Abs("assets/fixed-values.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		envVars["FIXED_VALUES"] = "replicaCount: 2\nimage: {tag: \"1.29\"}"
+		envVars["FIXED_VALUES_FILES"] = fixedValuesPath
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		deployment := readResources(filepath.Join(tmpDir, "object.yaml"))[2]
+		Expect(deployment.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeNumerically("==", 2))))
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers[0].(map[string]any)["image"]).To(Equal("my-registry/nginx:1.29"))
+	})
+
+	It("names the release and namespace as configured", func() {
+		envVars["RELEASE_NAME_TEMPLATE"] = "{{ .metadata.namespace }}-{{ .metadata.name }}"
+		envVars["TARGET_NAMESPACE"] = "web-system"
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		resources := readResources(filepath.Join(tmpDir, "object.yaml"))
+		Expect(resources[1].GetName()).To(Equal("non-default-test-object"))
+		Expect(resources[1].GetNamespace()).To(Equal("web-system"))
+
+		By("setting the namespace of the namespaced resources whose template leaves it out", func() {
+			Expect(resources[3].GetKind()).To(Equal("Job"))
+			Expect(resources[3].GetNamespace()).To(Equal("web-system"))
+			Expect(resources[0].GetKind()).To(Equal("ClusterRole"))
+			Expect(resources[0].GetNamespace()).To(BeEmpty())
+		})
+	})
+
+	It("releases the chart in the namespace of the request", func() {
+		envVars["NAMESPACE_FROM_REQUEST"] = "true"
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		resources := readResources(filepath.Join(tmpDir, "object.yaml"))
+		Expect(resources[1].GetNamespace()).To(Equal("non-default"))
+		Expect(resources[3].GetNamespace()).To(Equal("non-default"))
+		Expect(resources[0].GetNamespace()).To(BeEmpty())
+	})
+
+	It("includes the chart's CRDs and labels every resource", func() {
+		envVars["INCLUDE_CRDS"] = "true"
+		envVars["RESOURCE_LABELS"] = "team=web, kratix.io/promise-name=web"
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		resources := readResources(filepath.Join(tmpDir, "object.yaml"))
+		Expect(kinds(resources)).To(Equal([]string{"CustomResourceDefinition", "ClusterRole", "Service", "Deployment", "Job"}))
+		for _, resource := range resources {
+			Expect(resource.GetLabels()).To(SatisfyAll(
+				HaveKeyWithValue("team", "web"),
+				HaveKeyWithValue("kratix.io/promise-name", "web"),
+			))
+		}
+	})
+
+	It("leaves out the resources the Promise installs as dependencies", func() {
+		envVars["INCLUDE_CRDS"] = "true"
+		envVars["EXCLUDE_CRDS"] = "true"
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+		Expect(kinds(readResources(filepath.Join(tmpDir, "object.yaml")))).To(Equal([]string{"ClusterRole", "Service", "Deployment", "Job"}))
+
+		envVars["EXCLUDE_CLUSTER_RESOURCES"] = "true"
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+		Expect(kinds(readResources(filepath.Join(tmpDir, "object.yaml")))).To(Equal([]string{"Service", "Deployment", "Job"}))
+	})
+
+	It("writes each resource to its own file", func() {
+		envVars["SPLIT_OUTPUT"] = "true"
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+
+		files, err := os.ReadDir(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, file := range files {
+			names = append(names, file.Name())
+		}
+		Expect(names).To(ConsistOf(
+			"clusterrole_test-object-web.yaml",
+			"service_default_test-object.yaml",
+			"deployment_default_test-object.yaml",
+			"job_test-object-migrate.yaml",
+		))
+	})
+
+	It("fetches the chart with the credentials in HELM_CREDENTIALS_DIR", func() {
+		envVars["CHART_URL"] = serveChartRepo("assets/chart", "kratix", "s3cret")
+		envVars["CHART_NAME"] = "web"
+		session := runWithEnv(envVars)
+		Expect(session).To(gexec.Exit(1))
+		Expect(session.Err).To(gbytes.Say("failed to fetch chart web"))
+
+		credentialsDir := filepath.Join(tmpDir, "credentials")
+		Expect(os.Mkdir(credentialsDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credentialsDir, "username"), []byte("kratix"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credentialsDir, "password"), []byte("s3cret\n"), 0644)).To(Succeed())
+		envVars["HELM_CREDENTIALS_DIR"] = credentialsDir
+		envVars["KRATIX_OUTPUT_DIR"] = filepath.Join(tmpDir, "output")
+
+		Expect(runWithEnv(envVars)).To(gexec.Exit(0))
+		Expect(kinds(readResources(filepath.Join(tmpDir, "output", "object.yaml")))).To(ContainElement("Deployment"))
+	})
+
+	When("the stage is misconfigured", func() {
+		It("fails without a chart", func() {
+			delete(envVars, "CHART_URL")
+			session := runWithEnv(envVars)
+			Expect(session).To(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("CHART_URL environment variable is not set"))
+		})
+
+		It("fails when the chart cannot be found", func() {
+			envVars["CHART_URL"] = filepath.Join(tmpDir, "missing-chart")
+			session := runWithEnv(envVars)
+			Expect(session).To(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("failed to fetch chart %s", envVars["CHART_URL"]))
+		})
+
+		It("fails on release name templates the request cannot render", func() {
+			envVars["RELEASE_NAME_TEMPLATE"] = "{{ .metadata.uid }}"
+			session := runWithEnv(envVars)
+			Expect(session).To(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say(`failed to render RELEASE_NAME_TEMPLATE`))
+		})
+
+		It("fails to take the namespace from a request without one", func() {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "request.yaml"), []byte("metadata:\n  name: test-object\n"), 0644)).To(Succeed())
+			envVars["KRATIX_INPUT_FILE"] = filepath.Join(tmpDir, "request.yaml")
+			envVars["NAMESPACE_FROM_REQUEST"] = "true"
+			session := runWithEnv(envVars)
+			Expect(session).To(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("NAMESPACE_FROM_REQUEST is set but the request has no metadata.namespace"))
+		})
+
+		It("fails on invalid labels", func() {
+			envVars["RESOURCE_LABELS"] = "team"
+			session := runWithEnv(envVars)
+			Expect(session).To(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say(`invalid label "team" in RESOURCE_LABELS, expected KEY=VALUE`))
+		})
+	})
+})
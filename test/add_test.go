@@ -306,8 +306,13 @@ var _ = Describe("add", func() {
 
 						dockerfile := getPipelineDockerfile(dir, "promise", "configure", "pipeline0", "image")
 						Expect(dockerfile).To(ContainSubstring("FROM golang"))
+						Expect(dockerfile).To(ContainSubstring("COPY scripts/go.mod scripts/pipeline.go ./"))
+						Expect(dockerfile).To(ContainSubstring("RUN go mod tidy\n"))
+						Expect(dockerfile).NotTo(ContainSubstring("go.sum"))
 
-						Expect(sess.Out).To(gbytes.Say("For go containers, run 'go mod init' and 'go mod tidy' to manage your script's dependencies"))
+						Expect(pipelineWorkflowPathExists(dir, "promise", "configure", "pipeline0", "image", "scripts/go.mod")).To(BeTrue())
+						Expect(pipelineWorkflowPathExists(dir, "promise", "configure", "pipeline0", "image", "scripts/go.sum")).To(BeFalse())
+						Expect(sess.Out).NotTo(gbytes.Say("go mod init"))
 						Expect(sess.Out).To(gbytes.Say("Don't forget to build and push your image!"))
 					})
 				})
@@ -335,9 +340,207 @@ var _ = Describe("add", func() {
 
 						dockerfile := getPipelineDockerfile(dir, "promise", "configure", "pipeline0", "image")
 						Expect(dockerfile).To(ContainSubstring("FROM python"))
+						Expect(dockerfile).To(ContainSubstring("requirements.txt"))
+						Expect(pipelineWorkflowPathExists(dir, "promise", "configure", "pipeline0", "image", "scripts/requirements.txt")).To(BeTrue())
 						Expect(sess.Out).To(gbytes.Say("Don't forget to build and push your image!"))
 					})
 				})
+
+				DescribeTable("generates the script, Dockerfile and dependency manifests",
+					func(language, scriptFilename, dockerfileFrom string, extraFiles ...string) {
+						sess := r.run("add", "container", "resource/configure/pipeline0", "--image", "image:latest", "--dir", dir, "--language", language)
+
+						pipelines := getWorkflows(dir)
+						Expect(configurePipelines(pipelines, v1alpha1.WorkflowTypeResource)).To(HaveLen(1))
+						Expect(sess.Out).To(gbytes.Say("Customise your container by editing workflows/resource/configure/pipeline0/image/scripts/" + scriptFilename))
+
+						Expect(getPipelineScriptFilename(dir, "resource", "configure", "pipeline0", "image")).To(Equal(scriptFilename))
+						Expect(getPipelineDockerfile(dir, "resource", "configure", "pipeline0", "image")).To(ContainSubstring("FROM " + dockerfileFrom))
+						for _, file := range extraFiles {
+							Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "pipeline0", "image", file)).To(BeTrue(), file)
+						}
+						Expect(sess.Out).To(gbytes.Say("Don't forget to build and push your image!"))
+					},
+					Entry("typescript", "typescript", "pipeline.ts", "node", "scripts/package.json", "scripts/tsconfig.json"),
+					Entry("java", "java", "pipeline.java", "maven", "scripts/pom.xml"),
+					Entry("rust", "rust", "pipeline.rs", "rust", "scripts/Cargo.toml"),
+					Entry("kustomize", "kustomize", "pipeline.sh", `"alpine"`, "resources/kustomization.yaml"),
+					Entry("helm", "helm", "pipeline.sh", `"alpine"`, "resources/values.yaml"),
+				)
+
+				It("prints usage hints for the no-code languages", func() {
+					sess := r.run("add", "container", "resource/configure/pipeline0", "--image", "image:latest", "--dir", dir, "--language", "kustomize")
+					Expect(sess.Out).To(gbytes.Say("For kustomize containers, add your manifests to the resources directory"))
+					Expect(getPipelineScriptContents(dir, "resource", "configure", "pipeline0", "image")).To(ContainSubstring("kustomize build /resources"))
+				})
+			})
+
+			When("the --template flag is provided", func() {
+				var templateDir string
+
+				BeforeEach(func() {
+					var err error
+					templateDir, err = os.MkdirTemp("", "kratix-container-template")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(os.WriteFile(filepath.Join(templateDir, "Dockerfile.tpl"), []byte("FROM myorg/base:latest\nLABEL container={{ .ContainerName }}\n"), 0644)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(templateDir, "pipeline.sh.tpl"), []byte("echo {{ .PromiseName }} {{ .Lifecycle }}/{{ .Action }}/{{ .Pipeline }} {{ .Image | upper }} {{ .Language }}\n"), 0644)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(templateDir, "lint.yaml"), []byte("rules: {{ not rendered }}\n"), 0644)).To(Succeed())
+					Expect(os.MkdirAll(filepath.Join(templateDir, "resources"), 0755)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(templateDir, "resources", "defaults.yaml.tpl"), []byte("name: {{ .ContainerName }}\n"), 0644)).To(Succeed())
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(templateDir)
+				})
+
+				It("renders the template into the container directory", func() {
+					sess := r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--name", "app", "--dir", dir, "--template", templateDir)
+					Expect(sess.Out).To(gbytes.Say("Customise your container by editing workflows/resource/configure/instance/app/scripts/pipeline.sh"))
+
+					pipelines := getWorkflows(dir)
+					expectContainerProperties(configurePipelines(pipelines, v1alpha1.WorkflowTypeResource)[0].Spec.Containers[0], "myorg/app:v1", "app")
+
+					Expect(getPipelineDockerfile(dir, "resource", "configure", "instance", "app")).To(Equal("FROM myorg/base:latest\nLABEL container=app\n"))
+					Expect(getPipelineScriptContents(dir, "resource", "configure", "instance", "app")).To(Equal("echo postgresql resource/configure/instance MYORG/APP:V1 bash\n"))
+
+					lint, err := os.ReadFile(filepath.Join(dir, "workflows/resource/configure/instance/app/scripts/lint.yaml"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(lint)).To(Equal("rules: {{ not rendered }}\n"))
+
+					defaults, err := os.ReadFile(filepath.Join(dir, "workflows/resource/configure/instance/app/resources/defaults.yaml"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(defaults)).To(Equal("name: app\n"))
+				})
+
+				It("renders the Promise name for Promises generated with --split", func() {
+					splitDir, err := os.MkdirTemp("", "kratix-container-template-split")
+					Expect(err).NotTo(HaveOccurred())
+					defer os.RemoveAll(splitDir)
+					r.run("init", "promise", "redis", "--group", "syntasso.io", "--kind", "Redis", "--dir", splitDir, "--split")
+
+					r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--name", "app", "--dir", splitDir, "--template", templateDir)
+					Expect(getPipelineScriptContents(splitDir, "resource", "configure", "instance", "app")).To(Equal("echo redis resource/configure/instance MYORG/APP:V1 bash\n"))
+				})
+
+				It("errors when the template has no pipeline script", func() {
+					Expect(os.Remove(filepath.Join(templateDir, "pipeline.sh.tpl"))).To(Succeed())
+
+					r.exitCode = 1
+					sess := r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--dir", dir, "--template", templateDir)
+					Expect(sess.Err).To(gbytes.Say("has no pipeline script; add a pipeline.<extension> file to it or its scripts directory"))
+				})
+
+				It("uses KRATIX_CONTAINER_TEMPLATE as the default template", func() {
+					r.env = []string{"KRATIX_CONTAINER_TEMPLATE=" + templateDir}
+					r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--name", "app", "--dir", dir)
+					Expect(getPipelineDockerfile(dir, "resource", "configure", "instance", "app")).To(ContainSubstring("FROM myorg/base:latest"))
+				})
+
+				It("uses the built-in template of --language over KRATIX_CONTAINER_TEMPLATE", func() {
+					r.env = []string{"KRATIX_CONTAINER_TEMPLATE=" + templateDir}
+					r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--name", "app", "--dir", dir, "--language", "python")
+					Expect(getPipelineScriptFilename(dir, "resource", "configure", "instance", "app")).To(Equal("pipeline.py"))
+					Expect(getPipelineDockerfile(dir, "resource", "configure", "instance", "app")).NotTo(ContainSubstring("FROM myorg/base:latest"))
+				})
+
+				It("errors when --language is also set", func() {
+					r.exitCode = 1
+					sess := r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--dir", dir, "--template", templateDir, "--language", "go")
+					Expect(sess.Err).To(gbytes.Say(`if any flags in the group \[language template\] are set none of the others can be`))
+				})
+
+				It("errors when the template directory does not exist", func() {
+					r.exitCode = 1
+					sess := r.run("add", "container", "resource/configure/instance", "--image", "myorg/app:v1", "--dir", dir, "--template", filepath.Join(templateDir, "missing"))
+					Expect(sess.Err).To(gbytes.Say("failed to read template directory"))
+				})
+			})
+
+			When("adding a container to a delete workflow", func() {
+				It("generates a cleanup script instead of the configure script", func() {
+					r.run("add", "container", "resource/delete/instance", "--image", "cleanup:v1", "--dir", dir)
+					r.run("add", "container", "promise/delete/promise", "--image", "cleanup-py:v1", "--language", "python", "--dir", dir)
+
+					script := getPipelineScriptContents(dir, "resource", "delete", "instance", "cleanup")
+					Expect(script).To(ContainSubstring("This script runs when the resource is deleted"))
+					Expect(script).To(ContainSubstring(`echo "Cleaning up ${name} ${namespace}"`))
+					Expect(script).NotTo(ContainSubstring("Hello from"))
+
+					script = getPipelineScriptContents(dir, "promise", "delete", "promise", "cleanup-py")
+					Expect(script).To(ContainSubstring("This script runs when the promise is deleted"))
+					Expect(pipelineWorkflowPathExists(dir, "promise", "delete", "promise", "cleanup-py", "scripts/pipeline.py")).To(BeTrue())
+				})
+			})
+
+			When("container and pipeline settings are provided", func() {
+				It("sets them on the container and the pipeline", func() {
+					r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir,
+						"--env", "LOG_LEVEL=debug",
+						"--env-from-secret", "db-creds:password=DB_PASSWORD",
+						"--command", "sh -c",
+						"--args", "'echo hello'",
+						"--image-pull-policy", "IfNotPresent",
+						"--volume-mount", "creds:/creds:ro",
+						"--volume", "creds:secret:db-creds",
+						"--service-account", "db-admin",
+						"--rbac", "apiGroups=apps,resources=deployments,verbs=get;list",
+					)
+
+					pipeline := getWorkflows(dir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure][0]
+					container := pipeline.Spec.Containers[0]
+					Expect(container.Env).To(HaveLen(2))
+					Expect(container.Env[0].Name).To(Equal("LOG_LEVEL"))
+					Expect(container.Env[0].Value).To(Equal("debug"))
+					Expect(container.Env[1].Name).To(Equal("DB_PASSWORD"))
+					Expect(container.Env[1].ValueFrom.SecretKeyRef.Name).To(Equal("db-creds"))
+					Expect(container.Env[1].ValueFrom.SecretKeyRef.Key).To(Equal("password"))
+					Expect(container.Command).To(Equal([]string{"sh", "-c"}))
+					Expect(container.Args).To(Equal([]string{"echo hello"}))
+					Expect(string(container.ImagePullPolicy)).To(Equal("IfNotPresent"))
+					Expect(container.VolumeMounts).To(HaveLen(1))
+					Expect(container.VolumeMounts[0].Name).To(Equal("creds"))
+					Expect(container.VolumeMounts[0].MountPath).To(Equal("/creds"))
+					Expect(container.VolumeMounts[0].ReadOnly).To(BeTrue())
+
+					Expect(pipeline.Spec.Volumes).To(HaveLen(1))
+					Expect(pipeline.Spec.Volumes[0].Secret.SecretName).To(Equal("db-creds"))
+					Expect(pipeline.Spec.RBAC.ServiceAccount).To(Equal("db-admin"))
+					Expect(pipeline.Spec.RBAC.Permissions).To(HaveLen(1))
+					Expect(pipeline.Spec.RBAC.Permissions[0].APIGroups).To(Equal([]string{"apps"}))
+					Expect(pipeline.Spec.RBAC.Permissions[0].Resources).To(Equal([]string{"deployments"}))
+					Expect(pipeline.Spec.RBAC.Permissions[0].Verbs).To(Equal([]string{"get", "list"}))
+				})
+
+				It("only sets the pipeline's rbac when a service account or permission is given", func() {
+					r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir, "--volume", "cache:emptyDir")
+
+					pipeline := getWorkflows(dir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure][0]
+					Expect(pipeline.Spec.Volumes).To(HaveLen(1))
+					Expect(cat(filepath.Join(dir, "promise.yaml"))).NotTo(ContainSubstring("rbac:"))
+				})
+
+				It("does not add a permission the pipeline already has", func() {
+					permission := "apiGroups=apps,resources=deployments,verbs=get;list"
+					r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir, "--rbac", permission, "--rbac", permission)
+					r.run("add", "container", "resource/configure/instance", "--image", "other:latest", "--dir", dir, "--rbac", permission)
+
+					pipeline := getWorkflows(dir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure][0]
+					Expect(pipeline.Spec.Containers).To(HaveLen(2))
+					Expect(pipeline.Spec.RBAC.Permissions).To(HaveLen(1))
+				})
+
+				It("fails on invalid values", func() {
+					r.exitCode = 1
+					sess := r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir, "--image-pull-policy", "Sometimes")
+					Expect(sess.Err).To(gbytes.Say("invalid --image-pull-policy: Sometimes, expected one of: Always, IfNotPresent, Never"))
+
+					sess = r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir, "--volume", "creds:nfs")
+					Expect(sess.Err).To(gbytes.Say(`invalid --volume value "creds:nfs"`))
+
+					sess = r.run("add", "container", "resource/configure/instance", "--image", "image:latest", "--dir", dir, "--rbac", "resources=pods")
+					Expect(sess.Err).To(gbytes.Say("resources and verbs are required"))
+				})
 			})
 
 			When("the files were generated with the --split flag", func() {
@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -181,6 +182,72 @@ var _ = Describe("kratix build container", func() {
 			})
 		})
 
+		When("--build-args contains quoted values", func() {
+			It("keeps the quoted value as a single argument", func() {
+				session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql", "--build-args", `--label "description=a postgres image"`)
+				Expect(session).To(gbytes.Say("fake-docker build --tag syntasso/postgres-resource:v1.0.0 %s/workflows/promise/configure/postgresql/syntasso-postgres-resource --label description=a postgres image", dir))
+			})
+
+			It("errors when a quote is not terminated", func() {
+				r.exitCode = 1
+				session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql", "--build-args", `--label "description`)
+				Expect(session.Err).To(gbytes.Say("unterminated quote in arguments"))
+			})
+		})
+
+		When("the container has a build.yaml", func() {
+			var containerDir string
+
+			BeforeEach(func() {
+				containerDir = filepath.Join(dir, "workflows/promise/configure/postgresql/syntasso-postgres-resource")
+				buildConfig := `platforms:
+- linux/amd64
+- linux/arm64
+buildArgs:
+  VERSION: "1.2.3"
+  BASE: alpine
+target: runtime
+secrets:
+- id=npmrc,src=/tmp/.npmrc
+`
+				Expect(os.WriteFile(filepath.Join(containerDir, "build.yaml"), []byte(buildConfig), 0644)).To(Succeed())
+			})
+
+			It("uses the settings from the file", func() {
+				session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql", "--build-args", "--builder mybuilder")
+				Expect(session).To(gbytes.Say(
+					"fake-docker build --tag syntasso/postgres-resource:v1.0.0 %s --platform linux/amd64,linux/arm64 --build-arg BASE=alpine --build-arg VERSION=1.2.3 --target runtime --secret id=npmrc,src=/tmp/.npmrc --builder mybuilder",
+					containerDir,
+				))
+			})
+
+			When("a context is set", func() {
+				BeforeEach(func() {
+					Expect(os.WriteFile(filepath.Join(containerDir, "build.yaml"), []byte("context: ../../shared\n"), 0644)).To(Succeed())
+				})
+
+				It("builds from the context using the container's Dockerfile", func() {
+					session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql")
+					Expect(session).To(gbytes.Say(
+						"fake-docker build --tag syntasso/postgres-resource:v1.0.0 %s/workflows/promise/configure/shared --file %s/Dockerfile",
+						dir, containerDir,
+					))
+				})
+			})
+
+			When("the file contains unknown fields", func() {
+				BeforeEach(func() {
+					Expect(os.WriteFile(filepath.Join(containerDir, "build.yaml"), []byte("platform: linux/amd64\n"), 0644)).To(Succeed())
+				})
+
+				It("errors", func() {
+					r.exitCode = 1
+					session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql")
+					Expect(session.Err).To(gbytes.Say("failed to parse .*build.yaml"))
+				})
+			})
+		})
+
 		When("--push is set", func() {
 			It("builds and pushes the image", func() {
 				session := r.run("build", "container", "--dir", dir, "promise/configure/postgresql", "--push")
@@ -89,7 +89,7 @@ var _ = Describe("build", func() {
 
 		When("workflow files exist in the workflows directory", func() {
 			It("builds a promise from api, dependencies and workflow files", func() {
-				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--dir", promiseDir)
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--force", "--dir", promiseDir)
 				r.run("add", "container", "promise/configure/pipeline0", "--image", "psql:latest", "-n", "configure-image", "--dir", promiseDir)
 				r.run("add", "container", "resource/delete/pipeline0", "--image", "psql:latest", "-n", "delete-image", "--dir", promiseDir)
 				sess := r.run("build", "promise", "postgresql", "--dir", promiseDir)
@@ -123,7 +123,7 @@ var _ = Describe("build", func() {
 
 			When("workflow file is invalid", func() {
 				It("errors", func() {
-					r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--dir", promiseDir)
+					r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--force", "--dir", promiseDir)
 					r.run("add", "container", "promise/configure/pipeline0", "--image", "psql:latest", "-n", "configure-image", "--dir", promiseDir)
 					Expect(os.WriteFile(filepath.Join(promiseDir, "workflows/promise/configure/workflow.yaml"), []byte("not valid"), 0644)).To(Succeed())
 
@@ -211,7 +211,7 @@ var _ = Describe("build", func() {
 
 		When("--output flag is provided", func() {
 			It("outputs promise definition to provided filepath", func() {
-				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--dir", promiseDir)
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split", "--force", "--dir", promiseDir)
 				r.run("build", "promise", "postgresql", "--dir", promiseDir, "--output", filepath.Join(promiseDir, "promise.yaml"))
 				matchPromise(promiseDir, "postgresql", "syntasso.io", "v1alpha1", "Database", "database", "databases")
 			})
@@ -0,0 +1,109 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("--dry-run and --diff", func() {
+	var r *runner
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kratix-test")
+		Expect(err).NotTo(HaveOccurred())
+		r = &runner{exitCode: 0}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("prints the files init would create without writing them", func() {
+		sess := r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir, "--dry-run")
+		Expect(sess.Out).To(SatisfyAll(
+			gbytes.Say("Would create %s", filepath.Join(dir, "")),
+			gbytes.Say("Dry run: no files were changed"),
+		))
+
+		files, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	DescribeTable("init commands that add workflows to the Promise they generate",
+		func(args []string, split bool) {
+			args = append(args, "--group", "syntasso.io", "--kind", "Database", "--dir", dir, "--dry-run")
+			if split {
+				args = append(args, "--split")
+			}
+			sess := r.run(args...)
+			Expect(sess.Out).To(SatisfyAll(
+				gbytes.Say("Would create .*workflows/"),
+				gbytes.Say("Dry run: no files were changed"),
+			))
+
+			files, err := os.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		},
+		Entry("helm-promise with --with-delete", []string{"init", "helm-promise", "web", "--chart-path", helmChartAssetPath("0.1.0"), "--with-delete"}, false),
+		Entry("helm-promise with --with-delete and --split", []string{"init", "helm-promise", "web", "--chart-path", helmChartAssetPath("0.1.0"), "--with-delete"}, true),
+		Entry("pulumi-component-promise with --with-delete", []string{"init", "pulumi-component-promise", "db", "--schema", assetPath("pulumi", "schema.valid.json"), "--with-delete"}, false),
+		Entry("pulumi-component-promise with --with-delete and --split", []string{"init", "pulumi-component-promise", "db", "--schema", assetPath("pulumi", "schema.valid.json"), "--with-delete"}, true),
+		Entry("tf-module-promise with --with-delete", []string{"init", "tf-module-promise", "db", "--module-source", assetPath("terraform", "modules", "local", "basic"), "--with-delete"}, false),
+		Entry("tf-module-promise with --with-delete and --split", []string{"init", "tf-module-promise", "db", "--module-source", assetPath("terraform", "modules", "local", "basic"), "--with-delete"}, true),
+	)
+
+	When("the Promise exists", func() {
+		var promiseBefore []byte
+
+		BeforeEach(func() {
+			r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir)
+			r.run("update", "destination-selector", "env=dev", "--dir", dir)
+			var err error
+			promiseBefore, err = os.ReadFile(filepath.Join(dir, "promise.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("prints a unified diff of the changes with --diff", func() {
+			sess := r.run("update", "destination-selector", "env=prod", "--dir", dir, "--diff")
+			Expect(sess.Out).To(SatisfyAll(
+				gbytes.Say("Would update %s", filepath.Join(dir, "promise.yaml")),
+				gbytes.Say(`--- .*promise.yaml`),
+				gbytes.Say(`\+\+\+ .*promise.yaml`),
+				gbytes.Say(`-\s+env: dev`),
+				gbytes.Say(`\+\s+env: prod`),
+			))
+
+			promiseAfter, err := os.ReadFile(filepath.Join(dir, "promise.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(promiseAfter).To(Equal(promiseBefore))
+		})
+
+		It("lists the files add container would create and change", func() {
+			sess := r.run("add", "container", "resource/configure/instance", "--image", "syntasso/postgres:v1.0.0", "--dir", dir, "--dry-run")
+			Expect(sess.Out).To(SatisfyAll(
+				gbytes.Say("Would create .*workflows/resource/configure/instance/syntasso-postgres/scripts/pipeline.sh"),
+				gbytes.Say("Would update .*promise.yaml"),
+			))
+			Expect(filepath.Join(dir, "workflows")).NotTo(BeAnExistingFile())
+		})
+	})
+})
+
+// assetPath returns the absolute path of a file in test/assets, for commands
+// run from a temporary working directory.
+func assetPath(elem ...string) string {
+	cwd, _ := os.Getwd()
+	return filepath.Join(append([]string{cwd, "assets"}, elem...)...)
+}
+
+func helmChartAssetPath(version string) string {
+	return assetPath("helm", "versioned-chart", version)
+}
@@ -0,0 +1,172 @@
+package integration_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("health checks", func() {
+	var r *runner
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kratix-test")
+		Expect(err).NotTo(HaveOccurred())
+		r = &runner{exitCode: 0}
+		r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("add health-check", func() {
+		var definitionDir string
+
+		BeforeEach(func() {
+			definitionDir = filepath.Join(dir, "workflows", "resource", "configure", "instance", "health-definition")
+		})
+
+		It("adds a container writing the health definition of each resource to the resource configure workflow", func() {
+			session := r.run("add", "health-check", "--schedule", "*/5 * * * *", "--image", "syntasso/postgres-health:v1.0.0",
+				"--destination-selector", "env=prod", "--dir", dir)
+			Expect(session).To(SatisfyAll(
+				gbytes.Say("Build the health-definition image with: kratix build container resource/configure/instance --name health-definition"),
+				gbytes.Say("generated the health check container syntasso-postgres-health"),
+			))
+
+			Expect(cat(filepath.Join(dir, "promise.yaml"))).To(SatisfyAll(
+				ContainSubstring("name: health-definition"),
+				ContainSubstring("image: my-registry.io/my-org/kratix/health-definition:v0.0.1"),
+			))
+			Expect(cat(filepath.Join(definitionDir, "resources", "health-definition.yaml"))).To(SatisfyAll(
+				ContainSubstring("kind: HealthDefinition"),
+				ContainSubstring("promiseRef:\n    name: postgresql"),
+				ContainSubstring("schedule: '*/5 * * * *'"),
+				ContainSubstring("image: syntasso/postgres-health:v1.0.0"),
+			))
+			Expect(cat(filepath.Join(definitionDir, "resources", "destination-selectors.yaml"))).To(SatisfyAll(
+				ContainSubstring("directory: health"),
+				ContainSubstring("matchLabels:\n    env: prod"),
+			))
+			Expect(cat(filepath.Join(definitionDir, "scripts", "pipeline.sh"))).To(SatisfyAll(
+				ContainSubstring(".spec.resourceRef.name = strenv(NAME)"),
+				ContainSubstring("/resources/health-definition.yaml > /kratix/output/health/health-definition.yaml"),
+				ContainSubstring("/kratix/metadata/destination-selectors.yaml"),
+			))
+			Expect(filepath.Join(definitionDir, "Dockerfile")).To(BeAnExistingFile())
+
+			containerDir := filepath.Join(dir, "workflows", "resource", "healthcheck", "instance", "syntasso-postgres-health")
+			Expect(filepath.Join(containerDir, "Dockerfile")).To(BeAnExistingFile())
+			Expect(cat(filepath.Join(containerDir, "scripts", "pipeline.sh"))).To(ContainSubstring("/kratix/output/healthrecord.yaml"))
+		})
+
+		It("adds further containers to the existing health check", func() {
+			r.run("add", "health-check", "--schedule", "@hourly", "--image", "syntasso/first:v1.0.0", "--dir", dir)
+			r.run("add", "health-check", "--image", "syntasso/second:v1.0.0", "--language", "python", "--dir", dir)
+
+			Expect(cat(filepath.Join(definitionDir, "resources", "health-definition.yaml"))).To(SatisfyAll(
+				ContainSubstring("schedule: '@hourly'"),
+				ContainSubstring("image: syntasso/first:v1.0.0"),
+				ContainSubstring("image: syntasso/second:v1.0.0"),
+			))
+			Expect(strings.Count(cat(filepath.Join(dir, "promise.yaml")), "name: health-definition")).To(Equal(1))
+			Expect(filepath.Join(dir, "workflows", "resource", "healthcheck", "instance", "syntasso-second", "scripts", "pipeline.py")).To(BeAnExistingFile())
+		})
+
+		It("adds the health definition container to the last resource configure pipeline", func() {
+			r.run("add", "container", "resource/configure/provision", "--image", "syntasso/postgres-configure:v1.0.0", "--dir", dir)
+			r.run("add", "health-check", "--schedule", "@hourly", "--image", "syntasso/postgres-health:v1.0.0", "--dir", dir)
+
+			Expect(filepath.Join(dir, "workflows", "resource", "configure", "provision", "health-definition", "resources", "health-definition.yaml")).To(BeAnExistingFile())
+			Expect(filepath.Join(dir, "workflows", "resource", "configure", "instance")).NotTo(BeAnExistingFile())
+		})
+
+		It("builds a Promise whose resource configure workflow writes the health definition", func() {
+			splitDir, err := os.MkdirTemp("", "kratix-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(splitDir)
+			r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", splitDir, "--split")
+
+			r.run("add", "health-check", "--schedule", "@hourly", "--image", "syntasso/postgres-health:v1.0.0", "--promise-name", "postgresql", "--dir", splitDir)
+
+			session := r.run("build", "promise", "postgresql", "--dir", splitDir)
+			var promise v1alpha1.Promise
+			Expect(yaml.Unmarshal(bytes.TrimPrefix(session.Out.Contents(), []byte("No promise.yaml found, assuming --split was used to initialise the Promise")), &promise)).To(Succeed())
+			Expect(promise.Spec.Workflows.Resource.Configure).To(HaveLen(1))
+			pipeline := promise.Spec.Workflows.Resource.Configure[0].Object
+			Expect(pipeline["metadata"]).To(HaveKeyWithValue("name", "instance"))
+			Expect(pipeline["spec"]).To(HaveKeyWithValue("containers", ContainElement(SatisfyAll(
+				HaveKeyWithValue("name", "health-definition"),
+				HaveKeyWithValue("image", "my-registry.io/my-org/kratix/health-definition:v0.0.1"),
+			))))
+		})
+
+		It("requires a schedule for the first health check", func() {
+			r.exitCode = 1
+			session := r.run("add", "health-check", "--image", "syntasso/postgres-health:v1.0.0", "--dir", dir)
+			Expect(session.Err).To(gbytes.Say("--schedule is required when adding the first health check"))
+		})
+
+		It("validates the schedule", func() {
+			r.exitCode = 1
+			session := r.run("add", "health-check", "--schedule", "every minute", "--image", "syntasso/postgres-health:v1.0.0", "--dir", dir)
+			Expect(session.Err).To(gbytes.Say(`invalid schedule "every minute"`))
+		})
+
+		It("rejects languages without a health check template", func() {
+			r.exitCode = 1
+			session := r.run("add", "health-check", "--schedule", "@daily", "--image", "syntasso/postgres-health:v1.0.0", "--language", "rust", "--dir", dir)
+			Expect(session.Err).To(gbytes.Say("invalid language: rust, health checks support: bash, go, python"))
+		})
+	})
+
+	Describe("test pipeline", func() {
+		var outputDir string
+
+		BeforeEach(func() {
+			outputDir = filepath.Join(dir, "out")
+			r.run("add", "container", "resource/configure/instance", "--image", "syntasso/postgres-configure:v1.0.0",
+				"--env", "LOG_LEVEL=debug", "--command", "sh -c", "--args", "'echo hello'", "--dir", dir)
+		})
+
+		It("runs each container with the Kratix volumes mounted", func() {
+			session := r.run("test", "pipeline", "resource/configure/instance", "--dir", dir, "--output", outputDir)
+			Expect(session).To(SatisfyAll(
+				gbytes.Say("Running container syntasso-postgres-configure"),
+				gbytes.Say("fake-docker run --rm --volume %s/input:/kratix/input/ --volume %s/output:/kratix/output/ --volume %s/metadata:/kratix/metadata/", outputDir, outputDir, outputDir),
+				gbytes.Say("--env KRATIX_WORKFLOW_TYPE=resource --env KRATIX_WORKFLOW_ACTION=configure --env KRATIX_PROMISE_NAME=postgresql --env LOG_LEVEL=debug"),
+				gbytes.Say("--entrypoint sh syntasso/postgres-configure:v1.0.0 -c echo hello"),
+				gbytes.Say("Pipeline resource/configure/instance completed; output written to %s", outputDir),
+			))
+			Expect(filepath.Join(outputDir, "input", "object.yaml")).To(BeAnExistingFile())
+		})
+
+		It("prints the health record written by a health check", func() {
+			r.run("add", "health-check", "--schedule", "@hourly", "--image", "syntasso/postgres-health:v1.0.0", "--dir", dir)
+			Expect(os.MkdirAll(filepath.Join(outputDir, "output"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(outputDir, "output", "healthrecord.yaml"), []byte("state: healthy\n"), 0644)).To(Succeed())
+
+			session := r.run("test", "pipeline", "resource/healthcheck/instance", "--dir", dir, "--output", outputDir)
+			Expect(session).To(SatisfyAll(
+				gbytes.Say("--env KRATIX_WORKFLOW_ACTION=healthcheck --env KRATIX_PROMISE_NAME=postgresql syntasso/postgres-health:v1.0.0"),
+				gbytes.Say("Health record:\nstate: healthy"),
+			))
+		})
+
+		It("errors when the pipeline does not exist", func() {
+			r.exitCode = 1
+			session := r.run("test", "pipeline", "resource/configure/missing", "--dir", dir)
+			Expect(session.Err).To(gbytes.Say("pipeline missing not found in the resource/configure workflow"))
+		})
+	})
+})
@@ -71,7 +71,7 @@ var _ = Describe("InitCrossplanePromise", func() {
 				}
 				session = r.run(initPromiseCmd...)
 				generatedFiles = getFiles(workingDir)
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				expectFilesEqual(workingDir, expectedOutputDir, []string{"promise.yaml", "example-resource.yaml"})
 				Expect(cat(filepath.Join(workingDir, "README.md"))).To(SatisfyAll(
@@ -95,7 +95,7 @@ var _ = Describe("InitCrossplanePromise", func() {
 			})
 
 			It("generates the expected files", func() {
-				files := []string{"api.yaml", "workflows", "example-resource.yaml", "README.md", "dependencies.yaml"}
+				files := []string{"api.yaml", "workflows", "example-resource.yaml", "README.md", "dependencies.yaml", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				Expect(cat(filepath.Join(workingDir, "api.yaml"))).To(Equal(cat("assets/crossplane/expected-output-with-split/api.yaml")))
 				Expect(cat(filepath.Join(workingDir, "workflows/resource/configure/workflow.yaml"))).To(Equal(cat("assets/crossplane/expected-output-with-split/workflows/resource/configure/workflow.yaml")))
@@ -119,7 +119,7 @@ var _ = Describe("InitCrossplanePromise", func() {
 			})
 
 			It("generates the expected files", func() {
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				expectFilesEqual(workingDir, "assets/crossplane/expected-output-with-compositions", []string{"promise.yaml", "example-resource.yaml"})
 				Expect(cat(filepath.Join(workingDir, "README.md"))).To(SatisfyAll(
@@ -141,7 +141,7 @@ var _ = Describe("InitCrossplanePromise", func() {
 			})
 
 			It("generates the expected files", func() {
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				expectFilesEqual(workingDir, "assets/crossplane/expected-output-with-functions", []string{"promise.yaml"})
 				expectFilesEqual(workingDir, "assets/crossplane/expected-output", []string{"example-resource.yaml"})
@@ -164,7 +164,7 @@ var _ = Describe("InitCrossplanePromise", func() {
 			})
 
 			It("generates a single promise.yaml", func() {
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				expectFilesEqual(workingDir, "assets/crossplane/expected-output-with-skip-dependencies", []string{"promise.yaml", "example-resource.yaml"})
 				Expect(cat(filepath.Join(workingDir, "README.md"))).To(SatisfyAll(
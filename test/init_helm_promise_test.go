@@ -13,6 +13,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/syntasso/kratix/api/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 var _ = Describe("init helm-promise", func() {
@@ -46,7 +48,7 @@ var _ = Describe("init helm-promise", func() {
 		It("raises an error", func() {
 			r.exitCode = 1
 			Expect(r.run("init", "helm-promise", "postgresql", "--group", "syntasso.io", "--kind", "Database").Err).To(SatisfyAll(
-				gbytes.Say(`required flag\(s\) "chart-url" not set`),
+				gbytes.Say(`at least one of the flags in the group \[chart-url chart-path\] is required`),
 			))
 		})
 	})
@@ -58,7 +60,7 @@ var _ = Describe("init helm-promise", func() {
 
 			files, err := os.ReadDir(workingDir)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(files).To(HaveLen(5))
+			Expect(files).To(HaveLen(6))
 
 			By("generating an example-resource.yaml file", func() {
 				matchExampleResource(workingDir, "example-postgresql", "syntasso.io", "v1alpha1", "Database")
@@ -103,7 +105,7 @@ var _ = Describe("init helm-promise", func() {
 			Expect(session.Out).To(gbytes.Say("postgresql promise bootstrapped in the current directory"))
 			files, err := os.ReadDir(workingDir)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(files).To(HaveLen(3))
+			Expect(files).To(HaveLen(4))
 
 			By("generating an example-resource.yaml file", func() {
 				matchExampleResource(workingDir, "example-postgresql", "syntasso.io", "v1alpha1", "Database")
@@ -136,6 +138,301 @@ var _ = Describe("init helm-promise", func() {
 		})
 	})
 
+	Context("local helm chart", func() {
+		It("generates the API from the chart's values.schema.json", func() {
+			chartPath, err := filepath.Abs("assets/helm/chart-with-schema")
+			Expect(err).NotTo(HaveOccurred())
+			r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web")
+
+			props := getCRDProperties(workingDir, false)
+			Expect(props["replicaCount"].Type).To(Equal("integer"))
+			Expect(*props["replicaCount"].Minimum).To(Equal(1.0))
+			Expect(props["image"].Type).To(Equal("object"))
+			Expect(props["image"].Properties["repository"].Description).To(Equal("The image repository"))
+			Expect(props["image"].XPreserveUnknownFields).To(BeNil())
+			Expect(props["port"].XIntOrString).To(BeTrue())
+			Expect(props["nameOverride"].Type).To(Equal("string"))
+			Expect(props["nameOverride"].Nullable).To(BeTrue())
+
+			By("not requiring the values the chart's values.yaml sets", func() {
+				var promise v1alpha1.Promise
+				Expect(yaml.Unmarshal([]byte(cat(filepath.Join(workingDir, "promise.yaml"))), &promise)).To(Succeed())
+				_, crd, err := promise.GetAPI()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Required).To(BeEmpty())
+				Expect(props["image"].Required).To(BeEmpty())
+			})
+
+			By("documenting the API with the comments and values of values.yaml", func() {
+				Expect(props["replicaCount"].Description).To(Equal("Number of replicas"))
+				Expect(string(props["replicaCount"].Default.Raw)).To(Equal("1"))
+				Expect(string(props["image"].Properties["repository"].Default.Raw)).To(Equal(`"nginx"`))
+			})
+		})
+
+		It("infers the API from the chart's values without a values.schema.json", func() {
+			chartPath, err := filepath.Abs("assets/helm/chart-without-schema")
+			Expect(err).NotTo(HaveOccurred())
+			r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web")
+
+			props := getCRDProperties(workingDir, false)
+			Expect(props["replicaCount"].Type).To(Equal("number"))
+			Expect(props["port"].Type).To(Equal("number"))
+			Expect(*props["nameOverride"].XPreserveUnknownFields).To(BeTrue())
+
+			By("documenting the API with the comments and values of values.yaml", func() {
+				Expect(props["replicaCount"].Description).To(Equal("Number of replicas"))
+				Expect(props["image"].Properties["repository"].Description).To(Equal("Image repository"))
+				Expect(string(props["image"].Properties["repository"].Default.Raw)).To(Equal(`"nginx"`))
+				Expect(string(props["port"].Default.Raw)).To(Equal("80"))
+			})
+		})
+
+		It("only exposes the selected values and sets the fixed values in the pipeline", func() {
+			chartPath, err := filepath.Abs("assets/helm/chart-with-schema")
+			Expect(err).NotTo(HaveOccurred())
+			fixedValuesPath := filepath.Join(workingDir, "fixed-values.yaml")
+			Expect(os.WriteFile(fixedValuesPath, []byte("image:\n  repository: my-registry/nginx\n"), 0644)).To(Succeed())
+
+			session := r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+				"--include-values", "image.*,replicaCount,port,replicas", "--exclude-values", "port", "--fixed-values", fixedValuesPath)
+			Expect(session.Out).To(gbytes.Say(`warning: values pattern "replicas" matches no value of chart`))
+
+			props := getCRDProperties(workingDir, false)
+			Expect(props).To(SatisfyAll(HaveLen(2), HaveKey("replicaCount"), HaveKey("image")))
+			Expect(props["image"].Properties).To(SatisfyAll(HaveLen(1), HaveKey("tag")))
+
+			pipelines := getWorkflows(workingDir)["resource"]["configure"]
+			Expect(pipelines).To(HaveLen(1))
+			matchHelmResourceConfigurePipeline(pipelines[0], []corev1.EnvVar{
+				{Name: "CHART_URL", Value: chartPath},
+				{Name: "FIXED_VALUES", Value: "image:\n  repository: my-registry/nginx\n"},
+			})
+		})
+
+		Describe("installing cluster scoped resources as dependencies", func() {
+			var chartPath string
+
+			BeforeEach(func() {
+				var err error
+				chartPath, err = filepath.Abs("assets/helm/chart-with-crds")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			dependencyKinds := func() []string {
+				promiseYAML, err := os.ReadFile(filepath.Join(workingDir, "promise.yaml"))
+				Expect(err).NotTo(HaveOccurred())
+				var promise v1alpha1.Promise
+				Expect(yaml.Unmarshal(promiseYAML, &promise)).To(Succeed())
+				var kinds []string
+				for _, dependency := range promise.Spec.Dependencies {
+					kinds = append(kinds, dependency.GetKind()+"/"+dependency.GetName())
+				}
+				return kinds
+			}
+
+			It("adds the chart's CRDs to the Promise dependencies", func() {
+				session := r.run("init", "helm-promise", "widgets", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Widgets", "--crds-as-dependencies")
+
+				Expect(dependencyKinds()).To(ConsistOf("CustomResourceDefinition/widgets.example.com"))
+				Expect(string(session.Out.Contents())).NotTo(ContainSubstring("warning:"))
+				pipelines := getWorkflows(workingDir)["resource"]["configure"]
+				matchHelmResourceConfigurePipeline(pipelines[0], []corev1.EnvVar{
+					{Name: "CHART_URL", Value: chartPath},
+					{Name: "EXCLUDE_CRDS", Value: "true"},
+				})
+			})
+
+			It("adds every cluster scoped resource to the Promise dependencies", func() {
+				session := r.run("init", "helm-promise", "widgets", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Widgets", "--cluster-resources-as-dependencies")
+
+				Expect(dependencyKinds()).To(ConsistOf("CustomResourceDefinition/widgets.example.com", "ClusterRole/widgets-widgets"))
+				Expect(session.Out).To(gbytes.Say("warning: ClusterRole widgets-widgets is rendered from the release name or namespace; the dependency installs it once for release widgets in namespace default, not once per request"))
+				Expect(string(session.Out.Contents())).NotTo(ContainSubstring("warning: CustomResourceDefinition"))
+				pipelines := getWorkflows(workingDir)["resource"]["configure"]
+				matchHelmResourceConfigurePipeline(pipelines[0], []corev1.EnvVar{
+					{Name: "CHART_URL", Value: chartPath},
+					{Name: "EXCLUDE_CLUSTER_RESOURCES", Value: "true"},
+				})
+			})
+
+			It("writes the dependencies to dependencies.yaml with --split", func() {
+				r.run("init", "helm-promise", "widgets", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Widgets", "--crds-as-dependencies", "--split")
+
+				dependenciesYAML, err := os.ReadFile(filepath.Join(workingDir, "dependencies.yaml"))
+				Expect(err).NotTo(HaveOccurred())
+				var dependencies v1alpha1.Dependencies
+				Expect(yaml.Unmarshal(dependenciesYAML, &dependencies)).To(Succeed())
+				Expect(dependencies).To(HaveLen(1))
+				Expect(dependencies[0].GetName()).To(Equal("widgets.example.com"))
+			})
+
+			It("fails when the chart has no CRDs", func() {
+				chartPath, err := filepath.Abs("assets/helm/chart-without-schema")
+				Expect(err).NotTo(HaveOccurred())
+				r.exitCode = 1
+				session := r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web", "--crds-as-dependencies")
+				Expect(session.Err).To(gbytes.Say("has no cluster scoped resources to install as dependencies"))
+			})
+		})
+
+		Describe("release options", func() {
+			var chartPath string
+
+			BeforeEach(func() {
+				var err error
+				chartPath, err = filepath.Abs("assets/helm/chart-without-schema")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("passes the namespace and release name template to the pipeline", func() {
+				r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--namespace-from-request", "--release-name-template", "{{ .metadata.namespace }}-{{ .metadata.name }}")
+
+				pipelines := getWorkflows(workingDir)["resource"]["configure"]
+				matchHelmResourceConfigurePipeline(pipelines[0], []corev1.EnvVar{
+					{Name: "CHART_URL", Value: chartPath},
+					{Name: "NAMESPACE_FROM_REQUEST", Value: "true"},
+					{Name: "RELEASE_NAME_TEMPLATE", Value: "{{ .metadata.namespace }}-{{ .metadata.name }}"},
+				})
+
+				r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--target-namespace", "web-system", "--force")
+
+				pipelines = getWorkflows(workingDir)["resource"]["configure"]
+				matchHelmResourceConfigurePipeline(pipelines[0], []corev1.EnvVar{
+					{Name: "CHART_URL", Value: chartPath},
+					{Name: "TARGET_NAMESPACE", Value: "web-system"},
+				})
+			})
+
+			It("fails on invalid release options", func() {
+				r.exitCode = 1
+				session := r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--target-namespace", "Web_System")
+				Expect(session.Err).To(gbytes.Say(`invalid namespace "Web_System"`))
+
+				session = r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--release-name-template", "{{ .metadata.name")
+				Expect(session.Err).To(gbytes.Say(`invalid release name template "{{ .metadata.name"`))
+
+				session = r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--target-namespace", "web-system", "--namespace-from-request")
+				Expect(session.Err).To(gbytes.Say(`if any flags in the group \[target-namespace namespace-from-request\] are set none of the others can be`))
+			})
+		})
+
+		Describe("local charts", func() {
+			It("generates the Promise from a chart directory, vendoring the chart into the pipeline", func() {
+				chartPath, err := filepath.Abs("assets/helm/chart-without-schema")
+				Expect(err).NotTo(HaveOccurred())
+				session := r.run("init", "helm-promise", "web", "--chart-path", chartPath, "--group", "syntasso.io", "--kind", "Web")
+				Expect(session.Out).To(gbytes.Say("The chart is vendored into the resource configure container"))
+
+				Expect(getCRDProperties(workingDir, false)).To(HaveKey("replicaCount"))
+				pipeline := getWorkflows(workingDir)["resource"]["configure"][0]
+				Expect(pipeline.Spec.Containers[0].Image).To(Equal("my-registry.io/my-org/kratix/helm-resource-configure:v0.0.1"))
+				Expect(pipeline.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "CHART_URL", Value: "/resources/chart-without-schema-0.1.0.tgz"}))
+				Expect(filepath.Join(workingDir, "workflows", "resource", "configure", "instance-configure", "instance-configure", "resources", "chart-without-schema-0.1.0.tgz")).To(BeAnExistingFile())
+				Expect(cat(filepath.Join(workingDir, "README.md"))).NotTo(ContainSubstring("--vendor-chart"))
+			})
+
+			It("generates the Promise from a packaged chart, vendoring the chart into the pipeline", func() {
+				helmChart, err := loader.Load("assets/helm/chart-without-schema")
+				Expect(err).NotTo(HaveOccurred())
+				packagePath, err := chartutil.Save(helmChart, workingDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				session := r.run("init", "helm-promise", "web", "--chart-path", packagePath, "--group", "syntasso.io", "--kind", "Web")
+				Expect(session.Out).To(gbytes.Say("kratix build container resource/configure/instance-configure"))
+
+				Expect(getCRDProperties(workingDir, false)).To(HaveKey("replicaCount"))
+				pipeline := getWorkflows(workingDir)["resource"]["configure"][0]
+				Expect(pipeline.Spec.Containers[0].Image).To(Equal("my-registry.io/my-org/kratix/helm-resource-configure:v0.0.1"))
+				Expect(pipeline.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "CHART_URL", Value: "/resources/chart-without-schema-0.1.0.tgz"}))
+
+				containerDir := filepath.Join(workingDir, "workflows", "resource", "configure", "instance-configure", "instance-configure")
+				vendoredChart, err := loader.Load(filepath.Join(containerDir, "resources", "chart-without-schema-0.1.0.tgz"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vendoredChart.Name()).To(Equal("chart-without-schema"))
+				dockerfile, err := os.ReadFile(filepath.Join(containerDir, "Dockerfile"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(dockerfile)).To(ContainSubstring(`FROM "ghcr.io/syntasso/kratix-cli/helm-resource-configure:v0.4.0"`))
+			})
+
+			It("fails on invalid chart options", func() {
+				r.exitCode = 1
+				session := r.run("init", "helm-promise", "web", "--chart-url", "oci://example.com/web", "--chart-path", "web", "--group", "syntasso.io", "--kind", "Web")
+				Expect(session.Err).To(gbytes.Say(`if any flags in the group \[chart-url chart-path\] are set none of the others can be`))
+
+				session = r.run("init", "helm-promise", "web", "--chart-path", "missing-chart", "--group", "syntasso.io", "--kind", "Web")
+				Expect(session.Err).To(gbytes.Say("failed to load helm chart from missing-chart"))
+			})
+		})
+
+		Describe("private charts", func() {
+			var chartRepoURL string
+
+			BeforeEach(func() {
+				chartRepoURL = serveHelmChartRepoWithAuth("kratix", "s3cret", "assets/helm/versioned-chart/0.1.0")
+			})
+
+			It("fetches the chart with the given credentials", func() {
+				r.stdin = "s3cret\n"
+				r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--chart-name", "versioned-chart", "--group", "syntasso.io", "--kind", "Web",
+					"--username", "kratix", "--password-stdin")
+
+				props := getCRDProperties(workingDir, false)
+				Expect(props).To(HaveKey("image"))
+			})
+
+			It("fails without credentials", func() {
+				r.exitCode = 1
+				session := r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--chart-name", "versioned-chart", "--group", "syntasso.io", "--kind", "Web")
+				Expect(session.Err).To(gbytes.Say("failed to fetch helm chart"))
+			})
+
+			It("mounts the credentials Secret in the pipeline", func() {
+				chartPath, err := filepath.Abs("assets/helm/chart-without-schema")
+				Expect(err).NotTo(HaveOccurred())
+				r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+					"--credentials-secret", "chart-credentials", "--insecure-skip-tls-verify")
+
+				pipeline := getWorkflows(workingDir)["resource"]["configure"][0]
+				matchHelmResourceConfigurePipeline(pipeline, []corev1.EnvVar{
+					{Name: "CHART_URL", Value: chartPath},
+					{Name: "INSECURE_SKIP_TLS_VERIFY", Value: "true"},
+					{Name: "HELM_CREDENTIALS_DIR", Value: "/kratix/helm-credentials"},
+				})
+				Expect(pipeline.Spec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+					Name: "helm-credentials", MountPath: "/kratix/helm-credentials", ReadOnly: true,
+				}))
+				Expect(pipeline.Spec.Volumes).To(HaveLen(1))
+				Expect(pipeline.Spec.Volumes[0].Secret.SecretName).To(Equal("chart-credentials"))
+			})
+
+			It("fails on invalid auth options", func() {
+				r.exitCode = 1
+				session := r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--group", "syntasso.io", "--kind", "Web",
+					"--username", "kratix")
+				Expect(session.Err).To(gbytes.Say(`if any flags in the group \[username password-stdin\] are set they must all be set`))
+
+				session = r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--group", "syntasso.io", "--kind", "Web",
+					"--credentials-secret", "Chart_Credentials")
+				Expect(session.Err).To(gbytes.Say(`invalid secret name "Chart_Credentials"`))
+			})
+		})
+
+		It("fails on invalid values patterns", func() {
+			chartPath, err := filepath.Abs("assets/helm/chart-with-schema")
+			Expect(err).NotTo(HaveOccurred())
+			r.exitCode = 1
+			session := r.run("init", "helm-promise", "web", "--chart-url", chartPath, "--group", "syntasso.io", "--kind", "Web",
+				"--include-values", "image.[tag")
+			Expect(session.Err).To(gbytes.Say(`invalid values pattern "image.\[tag"`))
+		})
+	})
+
 	Context("helm chart integration", func() {
 		It("works with OCI helm chart", func() {
 			session := r.run("init", "helm-promise", "--chart-url", "oci://ghcr.io/jenkinsci/helm-charts/jenkins", "--chart-version", "5.8.86", "jenkins", "--group", "syntasso.io", "--kind", "Jenkins")
@@ -198,7 +495,7 @@ func getPipelines(dir string) []v1alpha1.Pipeline {
 func matchHelmResourceConfigurePipeline(pipeline v1alpha1.Pipeline, vars []corev1.EnvVar) {
 	ExpectWithOffset(1, pipeline.Spec.Containers).To(HaveLen(1))
 	ExpectWithOffset(1, pipeline.Spec.Containers[0].Name).To(Equal("instance-configure"))
-	ExpectWithOffset(1, pipeline.Spec.Containers[0].Image).To(Equal("ghcr.io/syntasso/kratix-cli/helm-resource-configure:v0.2.0"))
+	ExpectWithOffset(1, pipeline.Spec.Containers[0].Image).To(Equal("ghcr.io/syntasso/kratix-cli/helm-resource-configure:v0.4.0"))
 	ExpectWithOffset(1, pipeline.Spec.Containers[0].Env).To(ConsistOf(vars))
 }
 
@@ -29,7 +29,7 @@ var _ = Describe("InitOperatorPromise", func() {
 		r = &runner{exitCode: 0}
 		r.flags = map[string]string{
 			"--group":              "myorg.com",
-			"--kind":               "database",
+			"--kind":               "Database",
 			"--operator-manifests": "assets/operator",
 			"--dir":                workingDir,
 			"--api-schema-from":    "postgresqls.acid.zalan.do",
@@ -117,7 +117,7 @@ var _ = Describe("InitOperatorPromise", func() {
 				Expect(generatedFiles).To(ContainElement("README.md"))
 				readmeContents, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
 				Expect(err).ToNot(HaveOccurred())
-				Expect(string(readmeContents)).To(ContainSubstring("init operator-promise postgresql --operator-manifests assets/operator --api-schema-from postgresqls.acid.zalan.do --group myorg.com --kind database"))
+				Expect(string(readmeContents)).To(ContainSubstring("init operator-promise postgresql --operator-manifests assets/operator --api-schema-from postgresqls.acid.zalan.do --group myorg.com --kind Database"))
 				Expect(string(readmeContents)).NotTo(ContainSubstring("Pulumi PKO output"))
 			})
 		})
@@ -207,7 +207,7 @@ var _ = Describe("InitOperatorPromise", func() {
 			Expect(filepath.Join(workingDir, "README.md")).To(BeAnExistingFile())
 			readmeContents, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
 			Expect(err).ToNot(HaveOccurred())
-			Expect(string(readmeContents)).To(ContainSubstring("init operator-promise postgresql --operator-manifests assets/operator --api-schema-from postgresqls.acid.zalan.do --group myorg.com --kind database"))
+			Expect(string(readmeContents)).To(ContainSubstring("init operator-promise postgresql --operator-manifests assets/operator --api-schema-from postgresqls.acid.zalan.do --group myorg.com --kind Database"))
 			Expect(string(readmeContents)).NotTo(ContainSubstring("Pulumi PKO output"))
 		})
 
@@ -265,12 +265,12 @@ func expectCRDToMatchOperatorCRD(apiCRD apiextensionsv1.CustomResourceDefinition
 	Expect(apiCRD.Spec.Names).To(Equal(apiextensionsv1.CustomResourceDefinitionNames{
 		Plural:   "databases",
 		Singular: "database",
-		Kind:     "database",
+		Kind:     "Database",
 	}))
 	Expect(apiCRD.Spec.Versions).To(HaveLen(1))
 	Expect(apiCRD.Spec.Versions[0].Name).To(Equal("v1Stored"))
 	Expect(apiCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["kind"].Enum).To(HaveLen(1))
-	Expect(apiCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["kind"].Enum[0].Raw).To(BeEquivalentTo(`"database"`))
+	Expect(apiCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["kind"].Enum[0].Raw).To(BeEquivalentTo(`"Database"`))
 	Expect(apiCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["apiVersion"].Enum).To(HaveLen(1))
 	Expect(apiCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["apiVersion"].Enum[0].Raw).To(BeEquivalentTo(`"myorg.com/v1Stored"`))
 }
@@ -330,7 +330,7 @@ func expectExampleResourceToMatchOperatorResource(workingDir string) {
 
 	ExpectWithOffset(1, exampleResource.GetName()).To(Equal("example-database"))
 	ExpectWithOffset(1, exampleResource.GetNamespace()).To(Equal("default"))
-	ExpectWithOffset(1, exampleResource.GetKind()).To(Equal("database"))
+	ExpectWithOffset(1, exampleResource.GetKind()).To(Equal("Database"))
 	ExpectWithOffset(1, exampleResource.GetAPIVersion()).To(Equal("myorg.com/v1Stored"))
 
 	spec, found, err := unstructured.NestedMap(exampleResource.Object, "spec")
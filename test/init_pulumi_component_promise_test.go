@@ -410,6 +410,37 @@ resources:
 		))
 	})
 
+	DescribeTable("generates a resource delete workflow when --with-delete is set",
+		func(splitFlag bool) {
+			args := []string{
+				"init", "pulumi-component-promise", "mypromise",
+				"--schema", "./schema.valid.json",
+				"--group", "syntasso.io",
+				"--kind", "Database",
+				"--with-delete",
+			}
+			workflowFile := "promise.yaml"
+			if splitFlag {
+				args = append(args, "--split")
+				workflowFile = "workflows/resource/delete/workflow.yaml"
+			}
+			session := r.run(args...)
+			Expect(session.Out).To(gbytes.Say("Pulumi component Promise generated successfully."))
+
+			Expect(cat(filepath.Join(workingDir, workflowFile))).To(SatisfyAll(
+				ContainSubstring("name: instance-delete"),
+				ContainSubstring("name: pulumi-resource-delete"),
+				ContainSubstring("image: my-registry.io/my-org/kratix/pulumi-resource-delete:v0.0.1"),
+			))
+			containerDir := filepath.Join(workingDir, "workflows/resource/delete/instance-delete/pulumi-resource-delete")
+			Expect(cat(filepath.Join(containerDir, "scripts", "pipeline.sh"))).To(ContainSubstring("spec.destroyOnFinalize"))
+			Expect(filepath.Join(containerDir, "Dockerfile")).To(BeAnExistingFile())
+			Expect(cat(filepath.Join(workingDir, "README.md"))).To(ContainSubstring("--with-delete"))
+		},
+		Entry("flat", false),
+		Entry("split", true),
+	)
+
 	It("reconstructs the README command with schema and component args", func() {
 		session := r.run(
 			"init", "pulumi-component-promise", "mypromise",
@@ -47,6 +47,18 @@ var _ = Describe("init", func() {
 		})
 	})
 
+	When("called with --interactive", func() {
+		It("is listed in the help", func() {
+			session := r.run("init", "--help")
+			Expect(session.Out).To(gbytes.Say("--interactive\\s+Walk through initializing a Promise"))
+		})
+
+		It("requires a terminal", func() {
+			session := withExitCode(1).run("init", "--interactive")
+			Expect(session.Err).To(gbytes.Say("--interactive requires a terminal"))
+		})
+	})
+
 	Context("promise", func() {
 		When("called with --help", func() {
 			It("prints the help", func() {
@@ -69,13 +81,51 @@ var _ = Describe("init", func() {
 			})
 		})
 
+		When("called with invalid values", func() {
+			It("reports every invalid value before writing any file", func() {
+				session := withExitCode(1).run("init", "promise", "postgresql", "--group", "syntasso", "--kind", "database", "--version", "1.0", "--dir", workingDir)
+				Expect(session.Err).To(SatisfyAll(
+					gbytes.Say(`invalid group "syntasso": must contain at least one dot`),
+					gbytes.Say(`invalid kind "database": must be PascalCase.*\(did you mean "Database"\?\)`),
+					gbytes.Say(`invalid version "1.0"`),
+				))
+
+				files, err := os.ReadDir(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(BeEmpty())
+			})
+
+			It("rejects invalid Promise names", func() {
+				session := withExitCode(1).run("init", "promise", "PostgreSQL", "--group", "syntasso.io", "--kind", "Database")
+				Expect(session.Err).To(gbytes.Say(`invalid promise name "PostgreSQL".*\(did you mean "postgresql"\?\)`))
+			})
+		})
+
+		It("pluralises the kind", func() {
+			r.run("init", "promise", "policy", "--group", "syntasso.io", "--kind", "Policy")
+			promiseYAML, err := os.ReadFile(filepath.Join(workingDir, "promise.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(promiseYAML)).To(SatisfyAll(
+				ContainSubstring("name: policies.syntasso.io"),
+				ContainSubstring("plural: policies"),
+			))
+		})
+
+		It("asks for --plural when the plural of the kind is the same as its singular", func() {
+			session := withExitCode(1).run("init", "promise", "rds", "--group", "syntasso.io", "--kind", "RDS")
+			Expect(session.Err).To(gbytes.Say(`cannot pluralize kind "RDS".*set the plural with --plural`))
+
+			r.run("init", "promise", "rds", "--group", "syntasso.io", "--kind", "RDS", "--plural", "rdsinstances")
+			Expect(cat(filepath.Join(workingDir, "promise.yaml"))).To(ContainSubstring("plural: rdsinstances"))
+		})
+
 		It("generates the promise structure", func() {
 			session := r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database")
 			Expect(session.Out).To(gbytes.Say("postgresql promise bootstrapped in the current directory"))
 
 			files, err := os.ReadDir(workingDir)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(files).To(HaveLen(3))
+			Expect(files).To(HaveLen(4))
 
 			By("generating a promise.yaml file", func() {
 				matchPromise(workingDir, "postgresql", "syntasso.io", "v1alpha1", "Database", "database", "databases")
@@ -92,6 +142,123 @@ var _ = Describe("init", func() {
 			})
 		})
 
+		When("a Promise already exists in the directory", func() {
+			BeforeEach(func() {
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database")
+				r.run("add", "container", "resource/configure/instance", "--image", "myorg/configure:v1")
+				readme, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(filepath.Join(workingDir, "README.md"), append(readme, "## Hand-written notes\n"...), 0644)).To(Succeed())
+			})
+
+			It("refuses to overwrite it", func() {
+				session := withExitCode(1).run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", workingDir)
+				Expect(session.Err).To(gbytes.Say("a Promise already exists in .*; pass --merge .* or --force to overwrite it"))
+
+				readme, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(readme)).To(ContainSubstring("## Hand-written notes"))
+			})
+
+			It("overwrites it with --force", func() {
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--force")
+
+				readme, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(readme)).NotTo(ContainSubstring("## Hand-written notes"))
+				Expect(os.ReadFile(filepath.Join(workingDir, "promise.yaml"))).NotTo(ContainSubstring("myorg/configure:v1"))
+			})
+
+			It("regenerates the API and keeps the workflows and README with --merge", func() {
+				session := r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Store", "--merge")
+				Expect(session.Out).To(gbytes.Say("Kept the workflows, dependencies and README of the existing Promise in ."))
+
+				matchPromise(workingDir, "postgresql", "syntasso.io", "v1alpha1", "Store", "store", "stores")
+				Expect(os.ReadFile(filepath.Join(workingDir, "promise.yaml"))).To(ContainSubstring("myorg/configure:v1"))
+				Expect(filepath.Join(workingDir, "workflows", "resource", "configure", "instance")).To(BeADirectory())
+
+				readme, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(readme)).To(ContainSubstring("## Hand-written notes"))
+			})
+
+			It("does not accept --force and --merge together", func() {
+				session := withExitCode(1).run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--force", "--merge", "--dir", workingDir)
+				Expect(session.Err).To(gbytes.Say("--force and --merge cannot be used together"))
+			})
+		})
+
+		When("a split Promise already exists in the directory", func() {
+			It("keeps its workflow files with --merge", func() {
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--split")
+				r.run("add", "container", "resource/configure/instance", "--image", "myorg/configure:v1")
+
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Store", "--split", "--merge")
+
+				apiYAML, err := os.ReadFile(filepath.Join(workingDir, "api.yaml"))
+				Expect(err).NotTo(HaveOccurred())
+				var promiseCRD apiextensionsv1.CustomResourceDefinition
+				Expect(yaml.Unmarshal(apiYAML, &promiseCRD)).To(Succeed())
+				matchCRD(&promiseCRD, "syntasso.io", "v1alpha1", "Store", "store", "stores")
+
+				Expect(os.ReadFile(filepath.Join(workingDir, "workflows", "resource", "configure", "workflow.yaml"))).To(ContainSubstring("myorg/configure:v1"))
+			})
+		})
+
+		When("--template is provided", func() {
+			var templateDir string
+
+			BeforeEach(func() {
+				var err error
+				templateDir, err = os.MkdirTemp("", "kratix-promise-template")
+				Expect(err).NotTo(HaveOccurred())
+
+				manifest := `values:
+- name: team
+  description: Owning team
+  required: true
+- name: env
+  default: dev
+`
+				Expect(os.WriteFile(filepath.Join(templateDir, "kratix-template.yaml"), []byte(manifest), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(templateDir, "CODEOWNERS.tpl"), []byte("* @myorg/{{ .Values.team }}\n"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(templateDir, "README.md.tpl"), []byte("# {{ .Name }} ({{ .Kind }})\nOwned by {{ .Values.team }} in {{ .Values.env }}\n"), 0644)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(templateDir, ".github", "workflows"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(templateDir, ".github", "workflows", "ci.yaml"), []byte("on: [push]\n"), 0644)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(templateDir)
+			})
+
+			It("renders the template on top of the generated Promise", func() {
+				session := r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--template", templateDir, "--set", "team=data")
+				Expect(session.Out).To(gbytes.Say("postgresql promise bootstrapped in the current directory"))
+
+				matchPromise(workingDir, "postgresql", "syntasso.io", "v1alpha1", "Database", "database", "databases")
+
+				readme, err := os.ReadFile(filepath.Join(workingDir, "README.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(readme)).To(Equal("# postgresql (Database)\nOwned by data in dev\n"))
+
+				codeowners, err := os.ReadFile(filepath.Join(workingDir, "CODEOWNERS"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(codeowners)).To(Equal("* @myorg/data\n"))
+
+				Expect(filepath.Join(workingDir, ".github", "workflows", "ci.yaml")).To(BeARegularFile())
+				Expect(filepath.Join(workingDir, "kratix-template.yaml")).NotTo(BeAnExistingFile())
+			})
+
+			It("fails before writing any file when a required value is missing", func() {
+				session := withExitCode(1).run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--template", templateDir, "--dir", workingDir)
+				Expect(session.Err).To(gbytes.Say("missing required template values: team; set them with --set KEY=VALUE"))
+
+				files, err := os.ReadDir(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(BeEmpty())
+			})
+		})
+
 		When("the optional flags are provided", func() {
 			It("respects the provided values", func() {
 				subdir := filepath.Join(workingDir, "subdir")
@@ -117,7 +284,7 @@ var _ = Describe("init", func() {
 					By("generating different files for api, dependencies and workflows", func() {
 						files, err := os.ReadDir(workingDir)
 						Expect(err).NotTo(HaveOccurred())
-						Expect(files).To(HaveLen(4))
+						Expect(files).To(HaveLen(5))
 						var fileNames []string
 						for _, f := range files {
 							fileNames = append(fileNames, f.Name())
@@ -71,7 +71,7 @@ var _ = Describe("InitTerraformPromise", func() {
 			})
 
 			It("generates the expected files", func() {
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md", "workflows"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", "workflows", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				Expect(cat(filepath.Join(workingDir, "promise.yaml"))).To(Equal(cat("assets/terraform/expected-output/promise.yaml")))
 				Expect(cat(filepath.Join(workingDir, "example-resource.yaml"))).To(Equal(cat("assets/terraform/expected-output/example-resource.yaml")))
@@ -103,7 +103,7 @@ var _ = Describe("InitTerraformPromise", func() {
 			})
 
 			It("generates the expected files", func() {
-				files := []string{"api.yaml", "workflows", "example-resource.yaml", "README.md", "dependencies.yaml"}
+				files := []string{"api.yaml", "workflows", "example-resource.yaml", "README.md", "dependencies.yaml", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 				actualApi := cat(filepath.Join(workingDir, "api.yaml"))
 				api := cat("assets/terraform/expected-output-with-split/api.yaml")
@@ -155,7 +155,7 @@ var _ = Describe("InitTerraformPromise", func() {
 				}
 
 				// Adjust if your fixture has different files
-				files := []string{"promise.yaml", "example-resource.yaml", "README.md", "workflows"}
+				files := []string{"promise.yaml", "example-resource.yaml", "README.md", "workflows", ".kratix"}
 				Expect(generatedFiles).To(ConsistOf(files))
 
 				Expect(filepath.Join(dependenciesWorkflowPath, "Dockerfile")).To(BeAnExistingFile())
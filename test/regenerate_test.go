@@ -0,0 +1,101 @@
+package integration_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("regenerate", func() {
+	var r *runner
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kratix-test")
+		Expect(err).NotTo(HaveOccurred())
+		r = &runner{exitCode: 0}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("records the init command in .kratix/generator.yaml", func() {
+		r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir)
+
+		generator, err := os.ReadFile(filepath.Join(dir, ".kratix", "generator.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(generator)).To(Equal(`args:
+- postgresql
+command: init promise
+flags:
+  group:
+  - syntasso.io
+  kind:
+  - Database
+`))
+		Expect(filepath.Join(dir, ".kratix", "generated", "promise.yaml")).To(BeARegularFile())
+	})
+
+	It("fails for Promises not generated with init", func() {
+		session := withExitCode(1).run("regenerate", "--dir", dir)
+		Expect(session.Err).To(gbytes.Say("only Promises generated with `kratix init` can be regenerated"))
+	})
+
+	When("the Promise has local changes", func() {
+		BeforeEach(func() {
+			r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir)
+			r.run("add", "container", "resource/configure/instance", "--image", "myorg/configure:v1", "--dir", dir)
+			readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "README.md"), append(readme, "## Hand-written notes\n"...), 0644)).To(Succeed())
+		})
+
+		It("merges the regenerated Promise with them", func() {
+			session := r.run("regenerate", "--dir", dir, "--", "--version", "v2")
+			Expect(session.Out).To(gbytes.Say("Promise regenerated with `kratix init promise`"))
+
+			matchPromise(dir, "postgresql", "syntasso.io", "v2", "Database", "database", "databases")
+			Expect(os.ReadFile(filepath.Join(dir, "promise.yaml"))).To(ContainSubstring("myorg/configure:v1"))
+
+			readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(readme)).To(ContainSubstring("## Hand-written notes"))
+
+			Expect(os.ReadFile(filepath.Join(dir, ".kratix", "generator.yaml"))).To(ContainSubstring("version:\n  - v2"))
+		})
+
+		It("marks conflicting changes", func() {
+			readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "README.md"), bytes.ReplaceAll(readme, []byte("Database"), []byte("Db")), 0644)).To(Succeed())
+
+			session := withExitCode(1).run("regenerate", "--dir", dir, "--", "--kind", "Store")
+			Expect(session.Err).To(gbytes.Say("the regenerated Promise conflicts with local changes in README.md"))
+
+			readme, err = os.ReadFile(filepath.Join(dir, "README.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(readme)).To(SatisfyAll(
+				ContainSubstring("<<<<<<< local"),
+				ContainSubstring(">>>>>>> regenerated"),
+			))
+		})
+
+		It("only prints the changes with --dry-run", func() {
+			promiseBefore, err := os.ReadFile(filepath.Join(dir, "promise.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			session := r.run("regenerate", "--dir", dir, "--dry-run", "--", "--version", "v2")
+			Expect(session.Out).To(SatisfyAll(
+				gbytes.Say("Would update %s", filepath.Join(dir, "promise.yaml")),
+				gbytes.Say("Dry run: no files were changed"),
+			))
+			Expect(os.ReadFile(filepath.Join(dir, "promise.yaml"))).To(Equal(promiseBefore))
+		})
+	})
+})
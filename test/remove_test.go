@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/syntasso/kratix/api/v1alpha1"
+)
+
+var _ = Describe("remove", func() {
+	var r *runner
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kratix-remove-test")
+		Expect(err).NotTo(HaveOccurred())
+		r = &runner{exitCode: 0, dir: dir}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	When("it is called without a subcommand", func() {
+		It("prints the help", func() {
+			session := r.run("remove", "--help")
+			Expect(session.Out).To(gbytes.Say("Command to remove from Kratix resources"))
+		})
+	})
+
+	for _, split := range []bool{false, true} {
+		split := split
+		resourceConfigure := func() []v1alpha1.Pipeline {
+			if split {
+				return getWorkflowsFromSplitFile(dir, "resource", "configure")
+			}
+			return getWorkflows(dir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure]
+		}
+
+		Context(fmt.Sprintf("when the files were generated with --split=%t", split), func() {
+			BeforeEach(func() {
+				initArgs := []string{"init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", dir}
+				if split {
+					initArgs = append(initArgs, "--split")
+				}
+				r.run(initArgs...)
+				r.run("add", "container", "resource/configure/instance", "--image", "first:v1", "--dir", dir)
+				r.run("add", "container", "resource/configure/instance", "--image", "second:v1", "--dir", dir)
+				r.run("add", "container", "resource/configure/other", "--image", "third:v1", "--dir", dir)
+			})
+
+			Context("container", func() {
+				It("removes the container and keeps its files by default", func() {
+					sess := r.run("remove", "container", "resource/configure/instance", "--name", "first", "--dir", dir, "--yes")
+					Expect(sess.Out).To(gbytes.Say("Removed container first from pipeline resource/configure/instance"))
+
+					pipelines := resourceConfigure()
+					Expect(pipelines).To(HaveLen(2))
+					Expect(pipelines[0].Spec.Containers).To(HaveLen(1))
+					Expect(pipelines[0].Spec.Containers[0].Name).To(Equal("second"))
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "instance", "first", "Dockerfile")).To(BeTrue())
+				})
+
+				It("deletes the container directory with --delete-files", func() {
+					sess := r.run("remove", "container", "resource/configure/instance", "--name", "first", "--dir", dir, "--yes", "--delete-files")
+					Expect(sess.Out).To(gbytes.Say("Deleted .*workflows/resource/configure/instance/first"))
+
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "instance", "first", "")).To(BeFalse())
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "instance", "second", "Dockerfile")).To(BeTrue())
+				})
+
+				It("removes the pipeline when its last container is removed", func() {
+					sess := r.run("remove", "container", "resource/configure/other", "--name", "third", "--dir", dir, "--yes", "--delete-files")
+					Expect(sess.Out).To(gbytes.Say("and the now empty pipeline other"))
+
+					pipelines := resourceConfigure()
+					Expect(pipelines).To(HaveLen(1))
+					Expect(pipelines[0].Name).To(Equal("instance"))
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "other", "", "")).To(BeFalse())
+				})
+
+				It("does not change anything with --dry-run", func() {
+					sess := r.run("remove", "container", "resource/configure/instance", "--name", "first", "--dir", dir, "--delete-files", "--dry-run")
+					Expect(sess.Out).To(gbytes.Say("Would remove container first from pipeline resource/configure/instance"))
+					Expect(sess.Out).To(gbytes.Say("Would delete .*workflows/resource/configure/instance/first"))
+
+					Expect(resourceConfigure()[0].Spec.Containers).To(HaveLen(2))
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "instance", "first", "Dockerfile")).To(BeTrue())
+				})
+
+				It("asks for --yes when it cannot prompt for confirmation", func() {
+					r.exitCode = 1
+					sess := r.run("remove", "container", "resource/configure/instance", "--name", "first", "--dir", dir)
+					Expect(sess.Err).To(gbytes.Say("pass --yes to continue"))
+					Expect(resourceConfigure()[0].Spec.Containers).To(HaveLen(2))
+				})
+
+				It("fails when the container does not exist", func() {
+					r.exitCode = 1
+					sess := r.run("remove", "container", "resource/configure/instance", "--name", "missing", "--dir", dir, "--yes")
+					Expect(sess.Err).To(gbytes.Say("container missing not found in pipeline instance"))
+				})
+			})
+
+			Context("pipeline", func() {
+				It("removes the pipeline and deletes its files with --delete-files", func() {
+					sess := r.run("remove", "pipeline", "resource/configure/instance", "--dir", dir, "--yes", "--delete-files")
+					Expect(sess.Out).To(gbytes.Say("Removed pipeline resource/configure/instance"))
+
+					pipelines := resourceConfigure()
+					Expect(pipelines).To(HaveLen(1))
+					Expect(pipelines[0].Name).To(Equal("other"))
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "instance", "", "")).To(BeFalse())
+					Expect(pipelineWorkflowPathExists(dir, "resource", "configure", "other", "third", "Dockerfile")).To(BeTrue())
+				})
+
+				It("fails when the pipeline does not exist", func() {
+					r.exitCode = 1
+					sess := r.run("remove", "pipeline", "resource/delete/instance", "--dir", dir, "--yes")
+					Expect(sess.Err).To(gbytes.Say("pipeline instance not found in the resource/delete workflow"))
+				})
+			})
+		})
+	}
+})
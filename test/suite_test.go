@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +38,7 @@ type runner struct {
 	timeout  time.Duration
 	noPath   bool
 	Path     string
+	stdin    string
 }
 
 func withExitCode(exitCode int) *runner {
@@ -68,6 +70,9 @@ func (r *runner) run(args ...string) *gexec.Session {
 
 	cmd.Env = append(cmd.Env, "PATH="+cmdPath)
 	cmd.Env = append(cmd.Env, r.env...)
+	if r.stdin != "" {
+		cmd.Stdin = strings.NewReader(r.stdin)
+	}
 
 	session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 	ExpectWithOffset(1, err).NotTo(HaveOccurred())
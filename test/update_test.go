@@ -2,6 +2,8 @@ package integration_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"slices"
@@ -10,6 +12,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/syntasso/kratix/api/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -74,12 +79,22 @@ var _ = Describe("update", func() {
 					Expect(sess.Out).To(gbytes.Say("postgresql promise bootstrapped in"))
 				})
 
+				It("validates the new API values before updating the Promise", func() {
+					r.exitCode = 1
+					sess := r.run("update", "api", "--kind", "newKind", "--plural", "NewKinds", "--dir", dir)
+					Expect(sess.Err).To(SatisfyAll(
+						gbytes.Say(`invalid kind "newKind": must be PascalCase`),
+						gbytes.Say(`invalid plural "NewKinds"`),
+					))
+					matchPromise(dir, "postgresql", "syntasso.io", "v1alpha1", "Database", "database", "databases")
+				})
+
 				Context("api GVK", func() {
 					It("updates", func() {
-						sess := r.run("update", "api", "--kind", "NewKind", "--group", "newGroup", "--version", "v1beta4", "--plural", "newPlural", "--dir", dir)
+						sess := r.run("update", "api", "--kind", "NewKind", "--group", "newgroup.io", "--version", "v1beta4", "--plural", "newplurals", "--dir", dir)
 						Expect(sess.Out).To(gbytes.Say("Promise api updated"))
-						matchPromise(dir, "postgresql", "newGroup", "v1beta4", "NewKind", "newkind", "newPlural")
-						matchExampleResource(dir, "example-postgresql", "newGroup", "v1beta4", "NewKind")
+						matchPromise(dir, "postgresql", "newgroup.io", "v1beta4", "NewKind", "newkind", "newplurals")
+						matchExampleResource(dir, "example-postgresql", "newgroup.io", "v1beta4", "NewKind")
 					})
 				})
 
@@ -189,10 +204,10 @@ var _ = Describe("update", func() {
 				})
 
 				It("can update gvk of the api", func() {
-					sess := r.run("update", "api", "--kind", "NewKind", "--group", "newGroup", "--version", "v2beta4", "--plural", "newPlural")
+					sess := r.run("update", "api", "--kind", "NewKind", "--group", "newgroup.io", "--version", "v2beta4", "--plural", "newplurals")
 					Expect(sess.Out).To(gbytes.Say("Promise api updated"))
-					matchGvkInAPIFile(workingDir, "newGroup", "v2beta4", "NewKind", "newkind", "newPlural")
-					matchExampleResource(workingDir, "example-postgresql", "newGroup", "v2beta4", "NewKind")
+					matchGvkInAPIFile(workingDir, "newgroup.io", "v2beta4", "NewKind", "newkind", "newplurals")
+					matchExampleResource(workingDir, "example-postgresql", "newgroup.io", "v2beta4", "NewKind")
 				})
 
 				It("can add new properties and update existing properties to the promise api", func() {
@@ -662,6 +677,326 @@ var _ = Describe("update", func() {
 		})
 
 	})
+
+	Context("container", func() {
+		BeforeEach(func() {
+			r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database")
+			r.run("add", "container", "resource/configure/instance", "--image", "create-db:v1",
+				"--env", "LOG_LEVEL=debug", "--env", "REGION=eu", "--volume", "creds:secret:db-creds", "--volume-mount", "creds:/creds")
+		})
+
+		It("updates the container and its pipeline", func() {
+			sess := r.run("update", "container", "resource/configure/instance", "--name", "create-db",
+				"--image", "create-db:v2", "--env", "LOG_LEVEL-", "--env", "REGION=us", "--volume", "creds-", "--volume-mount", "creds-",
+				"--service-account", "db-admin")
+			Expect(sess.Out).To(gbytes.Say("Updated container create-db in pipeline resource/configure/instance"))
+
+			pipeline := getWorkflows(workingDir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure][0]
+			container := pipeline.Spec.Containers[0]
+			Expect(container.Image).To(Equal("create-db:v2"))
+			Expect(container.Env).To(HaveLen(1))
+			Expect(container.Env[0].Name).To(Equal("REGION"))
+			Expect(container.Env[0].Value).To(Equal("us"))
+			Expect(container.VolumeMounts).To(BeEmpty())
+			Expect(pipeline.Spec.Volumes).To(BeEmpty())
+			Expect(pipeline.Spec.RBAC.ServiceAccount).To(Equal("db-admin"))
+		})
+
+		It("fails when the container does not exist", func() {
+			r.exitCode = 1
+			sess := r.run("update", "container", "resource/configure/instance", "--name", "missing", "--env", "A=B")
+			Expect(sess.Err).To(gbytes.Say("container missing not found in pipeline instance"))
+		})
+	})
+
+	Context("pipeline-rbac", func() {
+		permissionsFor := func(split bool) []v1alpha1.Permission {
+			if split {
+				return getWorkflowsFromSplitFile(workingDir, "resource", "configure")[0].Spec.RBAC.Permissions
+			}
+			return getWorkflows(workingDir)[v1alpha1.WorkflowTypeResource][v1alpha1.WorkflowActionConfigure][0].Spec.RBAC.Permissions
+		}
+
+		for _, split := range []bool{false, true} {
+			split := split
+			When(fmt.Sprintf("the files were generated with --split=%t", split), func() {
+				BeforeEach(func() {
+					initArgs := []string{"init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database"}
+					if split {
+						initArgs = append(initArgs, "--split")
+					}
+					r.run(initArgs...)
+					r.run("add", "container", "resource/configure/instance", "--image", "create-db:v1")
+				})
+
+				It("adds and removes permissions", func() {
+					sess := r.run("update", "pipeline-rbac", "resource/configure/instance",
+						"--allow", "apiGroups=apps,resources=deployments,verbs=get;list",
+						"--allow", "resources=secrets,verbs=get",
+						"--resource-namespace", "default")
+					Expect(sess.Out).To(gbytes.Say("Pipeline resource/configure/instance permissions updated"))
+
+					permissions := permissionsFor(split)
+					Expect(permissions).To(HaveLen(2))
+					Expect(permissions[0].APIGroups).To(Equal([]string{"apps"}))
+					Expect(permissions[0].Resources).To(Equal([]string{"deployments"}))
+					Expect(permissions[0].Verbs).To(Equal([]string{"get", "list"}))
+					Expect(permissions[0].ResourceNamespace).To(Equal("default"))
+					Expect(permissions[1].APIGroups).To(Equal([]string{""}))
+					Expect(permissions[1].Resources).To(Equal([]string{"secrets"}))
+
+					sess = r.run("update", "pipeline-rbac", "resource/configure/instance")
+					Expect(sess.Out).To(gbytes.Say("apiGroups=apps,resources=deployments,verbs=get;list,resourceNamespace=default"))
+
+					r.run("update", "pipeline-rbac", "resource/configure/instance", "--remove", "apiGroups=apps,resources=deployments")
+					permissions = permissionsFor(split)
+					Expect(permissions).To(HaveLen(1))
+					Expect(permissions[0].Resources).To(Equal([]string{"secrets"}))
+				})
+
+				It("does not add a permission the pipeline already has", func() {
+					r.run("update", "pipeline-rbac", "resource/configure/instance", "--allow", "resources=secrets,verbs=get", "--allow", "resources=secrets,verbs=get")
+					r.run("update", "pipeline-rbac", "resource/configure/instance", "--allow", "resources=secrets,verbs=get")
+
+					Expect(permissionsFor(split)).To(HaveLen(1))
+				})
+			})
+		}
+
+		When("the input is invalid", func() {
+			BeforeEach(func() {
+				r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database")
+				r.run("add", "container", "resource/configure/instance", "--image", "create-db:v1")
+				r.exitCode = 1
+			})
+
+			It("rejects unknown verbs", func() {
+				sess := r.run("update", "pipeline-rbac", "resource/configure/instance", "--allow", "resources=pods,verbs=get;read")
+				Expect(sess.Err).To(gbytes.Say("unknown verb read"))
+			})
+
+			It("fails when there is no permission to remove", func() {
+				sess := r.run("update", "pipeline-rbac", "resource/configure/instance", "--remove", "resources=pods")
+				Expect(sess.Err).To(gbytes.Say(`no permission matching "resources=pods" found in pipeline instance`))
+			})
+
+			It("fails when the pipeline does not exist", func() {
+				sess := r.run("update", "pipeline-rbac", "resource/configure/missing", "--allow", "resources=pods,verbs=get")
+				Expect(sess.Err).To(gbytes.Say("pipeline missing not found in the resource/configure workflow"))
+			})
+		})
+	})
+
+	Context("pipeline", func() {
+		for _, split := range []bool{false, true} {
+			split := split
+			pipelinesFor := func(lifecycle, action string) []v1alpha1.Pipeline {
+				if split {
+					if !pipelineWorkflowPathExists(workingDir, lifecycle, action, "", "", "workflow.yaml") {
+						return nil
+					}
+					return getWorkflowsFromSplitFile(workingDir, lifecycle, action)
+				}
+				return getWorkflows(workingDir)[v1alpha1.Type(lifecycle)][v1alpha1.Action(action)]
+			}
+
+			When(fmt.Sprintf("the files were generated with --split=%t", split), func() {
+				BeforeEach(func() {
+					initArgs := []string{"init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database"}
+					if split {
+						initArgs = append(initArgs, "--split")
+					}
+					r.run(initArgs...)
+					r.run("add", "container", "resource/configure/instance", "--image", "first:v1")
+					r.run("add", "container", "resource/configure/instance", "--image", "second:v1")
+					r.run("add", "container", "resource/configure/instance", "--image", "third:v1")
+				})
+
+				It("moves a container before another container", func() {
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--move-container", "third", "--before", "first")
+					Expect(sess.Out).To(gbytes.Say("Moved container third in pipeline resource/configure/instance"))
+
+					containers := pipelinesFor("resource", "configure")[0].Spec.Containers
+					Expect([]string{containers[0].Name, containers[1].Name, containers[2].Name}).To(Equal([]string{"third", "first", "second"}))
+				})
+
+				It("moves a container after another container", func() {
+					r.run("update", "pipeline", "resource/configure/instance", "--move-container", "first", "--after", "third")
+
+					containers := pipelinesFor("resource", "configure")[0].Spec.Containers
+					Expect([]string{containers[0].Name, containers[1].Name, containers[2].Name}).To(Equal([]string{"second", "third", "first"}))
+				})
+
+				It("moves a pipeline before and after another pipeline of the workflow", func() {
+					r.run("add", "container", "resource/configure/notify", "--image", "notify:v1")
+					r.run("add", "container", "resource/configure/audit", "--image", "audit:v1")
+
+					sess := r.run("update", "pipeline", "resource/configure/audit", "--before", "instance")
+					Expect(sess.Out).To(gbytes.Say("Moved pipeline audit in the resource/configure workflow"))
+					Expect(pipelineNames(pipelinesFor("resource", "configure"))).To(Equal([]string{"audit", "instance", "notify"}))
+
+					r.run("update", "pipeline", "resource/configure/audit", "--after", "notify")
+					Expect(pipelineNames(pipelinesFor("resource", "configure"))).To(Equal([]string{"instance", "notify", "audit"}))
+				})
+
+				It("moves the pipeline to another workflow, before one of its pipelines", func() {
+					r.run("add", "container", "promise/configure/dependencies", "--image", "deps:v1")
+
+					r.run("update", "pipeline", "resource/configure/instance", "--move-to", "promise/configure", "--before", "dependencies")
+					Expect(pipelineNames(pipelinesFor("promise", "configure"))).To(Equal([]string{"instance", "dependencies"}))
+				})
+
+				It("fails to move a pipeline relative to a missing pipeline, without changing the Promise", func() {
+					r.exitCode = 1
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--move-to", "promise/configure", "--after", "missing")
+					Expect(sess.Err).To(gbytes.Say("pipeline missing not found in the workflow"))
+					Expect(pipelinesFor("resource", "configure")).To(HaveLen(1))
+				})
+
+				It("renames the pipeline and its directory", func() {
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--rename", "database")
+					Expect(sess.Out).To(gbytes.Say("Moved pipeline resource/configure/instance to resource/configure/database"))
+
+					pipelines := pipelinesFor("resource", "configure")
+					Expect(pipelines).To(HaveLen(1))
+					Expect(pipelines[0].Name).To(Equal("database"))
+					Expect(pipelines[0].Spec.Containers).To(HaveLen(3))
+					Expect(pipelineWorkflowPathExists(workingDir, "resource", "configure", "instance", "", "")).To(BeFalse())
+					Expect(pipelineWorkflowPathExists(workingDir, "resource", "configure", "database", "first", "Dockerfile")).To(BeTrue())
+				})
+
+				It("moves the pipeline to another workflow", func() {
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--move-to", "promise/configure")
+					Expect(sess.Out).To(gbytes.Say("Moved pipeline resource/configure/instance to promise/configure/instance"))
+
+					Expect(pipelinesFor("resource", "configure")).To(BeEmpty())
+					pipelines := pipelinesFor("promise", "configure")
+					Expect(pipelines).To(HaveLen(1))
+					Expect(pipelines[0].Name).To(Equal("instance"))
+					Expect(pipelines[0].Spec.Containers).To(HaveLen(3))
+					Expect(pipelineWorkflowPathExists(workingDir, "resource", "configure", "instance", "", "")).To(BeFalse())
+					Expect(pipelineWorkflowPathExists(workingDir, "promise", "configure", "instance", "first", "Dockerfile")).To(BeTrue())
+				})
+
+				It("fails when the destination pipeline already exists", func() {
+					r.run("add", "container", "promise/configure/instance", "--image", "other:v1")
+
+					r.exitCode = 1
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--move-to", "promise/configure")
+					Expect(sess.Err).To(gbytes.Say("pipeline instance already exists in the promise/configure workflow"))
+					Expect(pipelinesFor("resource", "configure")).To(HaveLen(1))
+				})
+
+				It("fails when the container to move does not exist", func() {
+					r.exitCode = 1
+					sess := r.run("update", "pipeline", "resource/configure/instance", "--move-container", "missing", "--before", "first")
+					Expect(sess.Err).To(gbytes.Say("container missing not found in pipeline instance"))
+				})
+			})
+		}
+
+		It("fails when there is nothing to update", func() {
+			r.exitCode = 1
+			sess := r.run("update", "pipeline", "resource/configure/instance")
+			Expect(sess.Err).To(gbytes.Say("nothing to update"))
+		})
+	})
+	Context("helm-chart", func() {
+		var chartRepoURL string
+
+		BeforeEach(func() {
+			chartRepoURL = serveHelmChartRepo("assets/helm/versioned-chart/0.1.0", "assets/helm/versioned-chart/0.2.0")
+			r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--chart-name", "versioned-chart", "--chart-version", "0.1.0", "--group", "syntasso.io", "--kind", "Web")
+		})
+
+		It("updates the chart version and merges the chart's values into the API", func() {
+			r.run("update", "api", "--property", "region:string")
+
+			sess := r.run("update", "helm-chart", "--version", "0.2.0")
+			Expect(sess.Out).To(SatisfyAll(
+				gbytes.Say(`\+ image.pullPolicy`),
+				gbytes.Say(`- ingress`),
+				gbytes.Say(`~ replicaCount`),
+				gbytes.Say(`\+ resources`),
+				gbytes.Say("Promise updated to version 0.2.0 of chart versioned-chart"),
+			))
+
+			props := getCRDProperties(workingDir, false)
+			Expect(props).To(SatisfyAll(HaveKey("region"), HaveKey("resources"), Not(HaveKey("ingress"))))
+			Expect(props["replicaCount"].Type).To(Equal("string"))
+			Expect(props["image"].Properties).To(HaveKey("pullPolicy"))
+
+			promiseYAML, err := os.ReadFile(filepath.Join(workingDir, "promise.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(promiseYAML)).To(SatisfyAll(
+				ContainSubstring("value: 0.2.0"),
+				Not(ContainSubstring("value: 0.1.0")),
+			))
+
+			generatorYAML, err := os.ReadFile(filepath.Join(workingDir, ".kratix", "generator.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(generatorYAML)).To(ContainSubstring("- 0.2.0"))
+		})
+
+		It("fails without changing the chart version when the API has no schema", func() {
+			promiseDir, err := os.MkdirTemp("", "kratix-update-helm-chart-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(promiseDir)
+			r.run("init", "helm-promise", "web", "--chart-url", chartRepoURL, "--chart-name", "versioned-chart", "--chart-version", "0.1.0", "--group", "syntasso.io", "--kind", "Web", "--dir", promiseDir, "--split")
+
+			apiPath := filepath.Join(promiseDir, "api.yaml")
+			var crd apiextensionsv1.CustomResourceDefinition
+			Expect(yamlsig.Unmarshal([]byte(cat(apiPath)), &crd)).To(Succeed())
+			crd.Spec.Versions[0].Schema = nil
+			apiYAML, err := yamlsig.Marshal(crd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(apiPath, apiYAML, 0644)).To(Succeed())
+
+			r.exitCode = 1
+			sess := r.run("update", "helm-chart", "--version", "0.2.0", "--dir", promiseDir)
+			Expect(sess.Err).To(gbytes.Say("api.yaml has no API schema to update"))
+			Expect(cat(filepath.Join(promiseDir, "workflows", "resource", "configure", "workflow.yaml"))).To(SatisfyAll(
+				ContainSubstring("value: 0.1.0"),
+				Not(ContainSubstring("value: 0.2.0")),
+			))
+		})
+
+		It("fails without changing the chart version when the generator config cannot be parsed", func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, ".kratix", "generator.yaml"), []byte("flags: [not a map"), 0644)).To(Succeed())
+
+			r.exitCode = 1
+			sess := r.run("update", "helm-chart", "--version", "0.2.0")
+			Expect(sess.Err).To(gbytes.Say("failed to parse .*generator.yaml"))
+			Expect(cat(filepath.Join(workingDir, "promise.yaml"))).To(SatisfyAll(
+				ContainSubstring("value: 0.1.0"),
+				Not(ContainSubstring("value: 0.2.0")),
+			))
+		})
+
+		It("fails for Promises with a vendored chart", func() {
+			promiseDir, err := os.MkdirTemp("", "kratix-update-helm-chart-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(promiseDir)
+			chartPath, err := filepath.Abs("assets/helm/versioned-chart/0.1.0")
+			Expect(err).NotTo(HaveOccurred())
+			r.run("init", "helm-promise", "web", "--chart-path", chartPath, "--group", "syntasso.io", "--kind", "Web", "--dir", promiseDir)
+
+			r.exitCode = 1
+			sess := r.run("update", "helm-chart", "--version", "0.2.0", "--dir", promiseDir)
+			Expect(sess.Err).To(gbytes.Say("the chart is vendored into the pipeline image; run 'kratix regenerate -- --chart-version 0.2.0'"))
+		})
+
+		It("fails for Promises not generated from a Helm chart", func() {
+			promiseDir, err := os.MkdirTemp("", "kratix-update-helm-chart-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(promiseDir)
+			r.run("init", "promise", "postgresql", "--group", "syntasso.io", "--kind", "Database", "--dir", promiseDir)
+
+			r.exitCode = 1
+			sess := r.run("update", "helm-chart", "--version", "0.2.0", "--dir", promiseDir)
+			Expect(sess.Err).To(gbytes.Say("only Promises generated with 'kratix init helm-promise' can be updated"))
+		})
+	})
 })
 
 func getDependencies(dir string, split bool) v1alpha1.Dependencies {
@@ -790,3 +1125,46 @@ func verifyGeneratedFilesWithImageFlag(workingDir string, files ...string) {
 		}))
 	})
 }
+
+func pipelineNames(pipelines []v1alpha1.Pipeline) []string {
+	var names []string
+	for _, pipeline := range pipelines {
+		names = append(names, pipeline.GetName())
+	}
+	return names
+}
+
+// serveHelmChartRepo packages the given charts into a Helm chart repository
+// served for the rest of the spec, and returns its URL.
+func serveHelmChartRepo(chartDirs ...string) string {
+	return serveHelmChartRepoWithAuth("", "", chartDirs...)
+}
+
+// serveHelmChartRepoWithAuth serves the charts like serveHelmChartRepo,
+// behind basic auth when a username is given.
+func serveHelmChartRepoWithAuth(username, password string, chartDirs ...string) string {
+	repoDir, err := os.MkdirTemp("", "kratix-helm-repo")
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	DeferCleanup(os.RemoveAll, repoDir)
+
+	for _, chartDir := range chartDirs {
+		helmChart, err := loader.Load(chartDir)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		_, err = chartutil.Save(helmChart, repoDir)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	}
+
+	fileServer := http.FileServer(http.Dir(repoDir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, _ := req.BasicAuth(); username != "" && (user != username || pass != password) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fileServer.ServeHTTP(w, req)
+	}))
+	DeferCleanup(server.Close)
+	index, err := repo.IndexDirectory(repoDir, server.URL)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, index.WriteFile(filepath.Join(repoDir, "index.yaml"), 0644)).To(Succeed())
+	return server.URL
+}